@@ -4,49 +4,94 @@ import (
 	"context"
 	"flag"
 	"fmt"
+	"io"
 	"log/slog"
 	"os"
 	"os/signal"
 	"path/filepath"
+	"strings"
 	"time"
 
 	"gh-review-notifier/internal/cache"
 	"gh-review-notifier/internal/github"
 	"gh-review-notifier/internal/monitor"
-	"gh-review-notifier/internal/notify"
 )
 
-const defaultAssignedQuery = "is:open is:pr archived:false user-review-requested:@me org:deseretdigital draft:false"
+const (
+	defaultAssignedQuery = "is:open is:pr archived:false user-review-requested:@me org:deseretdigital draft:false"
+	defaultReviewedQuery = "is:open is:pr archived:false reviewed-by:@me -author:@me org:deseretdigital"
+)
 
 func main() {
 	ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt)
 	defer cancel()
 
-	logger := slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{
-		Level: slog.LevelInfo,
-	}))
-
 	pollInterval := flag.Duration("interval", 3*time.Minute, "poll interval for checking GitHub")
 	assignedQuery := flag.String("assigned-query", defaultAssignedQuery, "GitHub search query for review requests")
 	author := flag.String("author", "", "GitHub username for authored PR tracking (defaults to authenticated user)")
 	cacheFile := flag.String("cache", "", "path to cache file (defaults to system config dir)")
+	subscriptionsFile := flag.String("subscriptions", "", "path to the watched pull request list (defaults to subscriptions.json next to the cache)")
+	draftQuery := flag.String("draft-query", "", "GitHub search query for draft review requests, tracked to report ready-for-review transitions (defaults to -assigned-query with draft:true; set empty to disable)")
+	notifyDrafts := flag.Bool("notify-drafts", false, "also notify on review requests and activity while a PR is still a draft")
+	teamQuery := flag.String("team-query", "", "additional GitHub search query for team review requests (e.g. team-review-requested:org/team)")
+	mutedTeams := flag.String("mute-teams", "", "comma-separated teams (org/slug) whose review requests should not notify")
+	issueQuery := flag.String("issue-query", "", "GitHub search query for issues to monitor for assignments and comments (e.g. is:open is:issue involves:@me); empty disables")
+	labelRules := flag.String("label-rules", "", "comma-separated labels to notify on when added to or removed from tracked PRs (prefix + for additions only, - for removals only)")
+	watchRepos := flag.String("watch-repos", "", "comma-separated repositories (owner/name) where every newly opened PR notifies")
+	watchReposReady := flag.Bool("watch-repos-ready", false, "also notify when a draft PR in a watched repository is marked ready")
+	deploymentRepos := flag.String("deployment-repos", "", "comma-separated repositories (owner/name) to check for deployments awaiting your approval")
+	workflowRepos := flag.String("workflow-repos", "", "comma-separated repositories (owner/name) whose default-branch workflows are watched for failures")
+	trackMergeQueue := flag.Bool("track-merge-queue", false, "notify on auto-merge and merge queue changes for authored PRs")
+	trackThreads := flag.Bool("track-threads", false, "notify when all your review threads on a PR are resolved, and when threads on your PRs are unresolved")
+	reviewedQuery := flag.String("reviewed-query", defaultReviewedQuery, "GitHub search query for PRs you reviewed, used with -track-threads")
+	closedGrace := flag.Duration("closed-grace", 24*time.Hour, "keep notifying on comments and reviews on your PRs for this long after they are merged or closed (0 disables)")
+	notifyEdits := flag.Bool("notify-edits", true, "notify when an already-seen comment or review on an authored PR is edited")
+	notifyQuietUpdates := flag.Bool("notify-quiet-updates", false, "notify on assigned PR updates with no commits, comments, title or label changes")
+	notifierOpts := registerNotifierFlags()
 	flag.Parse()
+	if !flagSet("draft-query") {
+		*draftQuery = draftQueryFor(*assignedQuery)
+	}
 
-	client := github.NewClient(logger)
+	logOutput := os.Stdout
+	if notifierOpts.usesStdout() {
+		logOutput = os.Stderr
+	}
+	logger := slog.New(slog.NewTextHandler(logOutput, &slog.HandlerOptions{
+		Level: slog.LevelInfo,
+	}))
 
 	var err error
-	if *author == "" {
-		*author, err = client.CurrentUserLogin(ctx)
+	if *cacheFile == "" {
+		*cacheFile, err = defaultCachePath()
 		if err != nil {
-			logger.Error("failed to resolve current GitHub user", slog.String("error", err.Error()))
+			logger.Error("failed to determine default cache path", slog.String("error", err.Error()))
 			os.Exit(1)
 		}
 	}
+	if *subscriptionsFile == "" {
+		*subscriptionsFile = filepath.Join(filepath.Dir(*cacheFile), "subscriptions.json")
+	}
 
-	if *cacheFile == "" {
-		*cacheFile, err = defaultCachePath()
+	switch cmd := flag.Arg(0); cmd {
+	case "":
+	case "watch", "unwatch":
+		if err := runWatchCommand(cmd, flag.Arg(1), *subscriptionsFile); err != nil {
+			fmt.Fprintf(os.Stderr, "%s: %v\n", cmd, err)
+			os.Exit(1)
+		}
+		return
+	default:
+		fmt.Fprintf(os.Stderr, "unknown command %q (expected watch or unwatch)\n", cmd)
+		os.Exit(2)
+	}
+
+	client := github.NewClient(logger)
+
+	if *author == "" {
+		*author, err = client.CurrentUserLogin(ctx)
 		if err != nil {
-			logger.Error("failed to determine default cache path", slog.String("error", err.Error()))
+			logger.Error("failed to resolve current GitHub user", slog.String("error", err.Error()))
 			os.Exit(1)
 		}
 	}
@@ -57,12 +102,36 @@ func main() {
 		os.Exit(1)
 	}
 
+	notifier, err := newNotifier(notifierOpts, logger)
+	if err != nil {
+		logger.Error("failed to configure notifier", slog.String("error", err.Error()))
+		os.Exit(1)
+	}
+
 	mon := monitor.NewMonitor(monitor.Config{
 		PollInterval:  *pollInterval,
 		AssignedQuery: *assignedQuery,
 		Author:        *author,
 		CacheFile:     *cacheFile,
-	}, client, notify.NewNotifier(logger), state, logger)
+
+		NotifyQuietUpdates: *notifyQuietUpdates,
+		DraftQuery:         *draftQuery,
+		NotifyDrafts:       *notifyDrafts,
+		TeamQuery:          *teamQuery,
+		MutedTeams:         splitList(*mutedTeams),
+		NotifyEdits:        *notifyEdits,
+		IssueQuery:         *issueQuery,
+		LabelRules:         monitor.ParseLabelRules(*labelRules),
+		DeploymentRepos:    splitList(*deploymentRepos),
+		WorkflowRepos:      splitList(*workflowRepos),
+		SubscriptionsFile:  *subscriptionsFile,
+		WatchedRepos:       splitList(*watchRepos),
+		NotifyWatchedReady: *watchReposReady,
+		TrackMergeQueue:    *trackMergeQueue,
+		TrackThreads:       *trackThreads,
+		ReviewedQuery:      *reviewedQuery,
+		ClosedGracePeriod:  *closedGrace,
+	}, client, notifier, state, logger)
 
 	logger.Info("starting gh-review-notifier",
 		slog.Duration("interval", *pollInterval),
@@ -70,12 +139,53 @@ func main() {
 		slog.String("cache", *cacheFile),
 	)
 
-	if err := mon.Run(ctx); err != nil {
-		logger.Error("monitor stopped with error", slog.String("error", err.Error()))
+	runErr := mon.Run(ctx)
+	// Send queued notifications and let running hooks exit before the
+	// process does.
+	if closer, ok := notifier.(io.Closer); ok {
+		if err := closer.Close(); err != nil {
+			logger.Warn("failed to close notifier", slog.String("error", err.Error()))
+		}
+	}
+	if runErr != nil {
+		logger.Error("monitor stopped with error", slog.String("error", runErr.Error()))
 		os.Exit(1)
 	}
 }
 
+// runWatchCommand adds or removes a pull request URL from the subscriptions
+// file. A running daemon picks up the change on its next poll.
+func runWatchCommand(cmd, raw, path string) error {
+	if raw == "" {
+		return fmt.Errorf("usage: gh-review-notifier %s <pull request url>", cmd)
+	}
+	repo, number, err := github.PullRequestFromURL(raw)
+	if err != nil {
+		return err
+	}
+	prURL := fmt.Sprintf("https://github.com/%s/pull/%d", repo, number)
+
+	subs, err := cache.LoadSubscriptions(path)
+	if err != nil {
+		return err
+	}
+	var changed bool
+	if cmd == "watch" {
+		changed = subs.Add(prURL)
+	} else {
+		changed = subs.Remove(prURL)
+	}
+	if !changed {
+		fmt.Printf("%s#%d: nothing to %s\n", repo, number, cmd)
+		return nil
+	}
+	if err := cache.SaveSubscriptions(path, subs); err != nil {
+		return err
+	}
+	fmt.Printf("%s#%d: %sed\n", repo, number, cmd)
+	return nil
+}
+
 func defaultCachePath() (string, error) {
 	cfgDir, err := os.UserConfigDir()
 	if err != nil {
@@ -83,3 +193,40 @@ func defaultCachePath() (string, error) {
 	}
 	return filepath.Join(cfgDir, "gh-review-notifier", "state.json"), nil
 }
+
+func flagSet(name string) bool {
+	set := false
+	flag.Visit(func(f *flag.Flag) {
+		if f.Name == name {
+			set = true
+		}
+	})
+	return set
+}
+
+// draftQueryFor turns the assigned query into one for drafts by replacing
+// its draft: qualifier, or adding one.
+func draftQueryFor(assigned string) string {
+	terms := strings.Fields(assigned)
+	replaced := false
+	for i, term := range terms {
+		if strings.HasPrefix(term, "draft:") {
+			terms[i] = "draft:true"
+			replaced = true
+		}
+	}
+	if !replaced {
+		terms = append(terms, "draft:true")
+	}
+	return strings.Join(terms, " ")
+}
+
+func splitList(raw string) []string {
+	var items []string
+	for _, item := range strings.Split(raw, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
@@ -0,0 +1,208 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log/slog"
+	"os"
+	"slices"
+	"strings"
+	"time"
+
+	"gh-review-notifier/internal/notify"
+)
+
+// notifierFlags holds the flags that select and configure the notification
+// backends.
+type notifierFlags struct {
+	backends string
+	routes   string
+
+	slackWebhook string
+	slackToken   string
+	slackChannel string
+
+	webhookURL      string
+	webhookSecret   string
+	webhookHeaders  map[string]string
+	webhookTimeout  time.Duration
+	webhookAttempts int
+
+	emailHost         string
+	emailPort         int
+	emailSecurity     string
+	emailUsername     string
+	emailPassword     string
+	emailFrom         string
+	emailTo           string
+	emailTextTemplate string
+	emailHTMLTemplate string
+
+	ntfyServer string
+	ntfyTopic  string
+	ntfyToken  string
+
+	matrixHomeserver string
+	matrixRoom       string
+	matrixToken      string
+	matrixEditWindow time.Duration
+
+	execCommand     string
+	execTimeout     time.Duration
+	execConcurrency int
+
+	jsonlFile    string
+	jsonlMaxMB   int
+	jsonlBackups int
+}
+
+func registerNotifierFlags() *notifierFlags {
+	f := &notifierFlags{}
+	flag.StringVar(&f.backends, "notifier", "desktop", "comma-separated notification backends: desktop, slack, webhook, email, ntfy, matrix, exec or jsonl")
+	flag.StringVar(&f.routes, "routes", "", "path to a JSON file of rules selecting which events each backend receives")
+	flag.StringVar(&f.slackWebhook, "slack-webhook", "", "Slack incoming webhook URL (defaults to $SLACK_WEBHOOK_URL)")
+	flag.StringVar(&f.slackToken, "slack-token", "", "Slack bot token for chat.postMessage (defaults to $SLACK_BOT_TOKEN)")
+	flag.StringVar(&f.slackChannel, "slack-channel", "", "Slack channel to post to with -slack-token")
+	flag.StringVar(&f.webhookURL, "webhook-url", "", "URL that receives a JSON event for each notification")
+	flag.StringVar(&f.webhookSecret, "webhook-secret", "", "HMAC-SHA256 key for the X-Signature header (defaults to $WEBHOOK_SECRET)")
+	flag.Func("webhook-header", "extra `Name: value` header sent with webhook requests (repeatable)", func(raw string) error {
+		name, value, ok := strings.Cut(raw, ":")
+		if !ok || strings.TrimSpace(name) == "" {
+			return fmt.Errorf("expected Name: value, got %q", raw)
+		}
+		if f.webhookHeaders == nil {
+			f.webhookHeaders = map[string]string{}
+		}
+		f.webhookHeaders[strings.TrimSpace(name)] = strings.TrimSpace(value)
+		return nil
+	})
+	flag.DurationVar(&f.webhookTimeout, "webhook-timeout", 10*time.Second, "timeout for each webhook delivery attempt")
+	flag.IntVar(&f.webhookAttempts, "webhook-attempts", 3, "delivery attempts per webhook event, with exponential backoff")
+	flag.StringVar(&f.emailHost, "email-host", "", "SMTP server host")
+	flag.IntVar(&f.emailPort, "email-port", 0, "SMTP server port (defaults to 587, 465 with -email-security=tls, or 25 with none)")
+	flag.StringVar(&f.emailSecurity, "email-security", "starttls", "SMTP transport security: starttls, tls or none")
+	flag.StringVar(&f.emailUsername, "email-username", "", "SMTP username for PLAIN auth")
+	flag.StringVar(&f.emailPassword, "email-password", "", "SMTP password (defaults to $SMTP_PASSWORD)")
+	flag.StringVar(&f.emailFrom, "email-from", "", "sender address for notification emails")
+	flag.StringVar(&f.emailTo, "email-to", "", "comma-separated recipient addresses")
+	flag.StringVar(&f.emailTextTemplate, "email-text-template", "", "path to a text/template file for the plain-text body")
+	flag.StringVar(&f.emailHTMLTemplate, "email-html-template", "", "path to an html/template file for the HTML body")
+	flag.StringVar(&f.ntfyServer, "ntfy-server", "https://ntfy.sh", "ntfy server URL")
+	flag.StringVar(&f.ntfyTopic, "ntfy-topic", "", "ntfy topic to publish to")
+	flag.StringVar(&f.ntfyToken, "ntfy-token", "", "ntfy access token (defaults to $NTFY_TOKEN)")
+	flag.StringVar(&f.matrixHomeserver, "matrix-homeserver", "", "Matrix homeserver URL (e.g. https://matrix.example.org)")
+	flag.StringVar(&f.matrixRoom, "matrix-room", "", "Matrix room ID to post to (e.g. !abc123:example.org)")
+	flag.StringVar(&f.matrixToken, "matrix-token", "", "Matrix access token (defaults to $MATRIX_TOKEN)")
+	flag.DurationVar(&f.matrixEditWindow, "matrix-edit-window", time.Hour, "edit a PR's last Matrix message instead of posting a new one for this long")
+	flag.StringVar(&f.execCommand, "exec-command", "", "shell command run per notification with the event JSON on stdin")
+	flag.DurationVar(&f.execTimeout, "exec-timeout", 30*time.Second, "timeout for each -exec-command run")
+	flag.IntVar(&f.execConcurrency, "exec-concurrency", 4, "maximum -exec-command runs in flight at once")
+	flag.StringVar(&f.jsonlFile, "jsonl-file", "", "file to append JSON Lines events to (defaults to stdout)")
+	flag.IntVar(&f.jsonlMaxMB, "jsonl-max-mb", 10, "rotate -jsonl-file once it reaches this many megabytes (0 disables)")
+	flag.IntVar(&f.jsonlBackups, "jsonl-backups", 3, "rotated -jsonl-file copies to keep (0 truncates the file on rotation instead)")
+	return f
+}
+
+// usesStdout reports whether notifications are written to stdout, in which
+// case logs must go elsewhere.
+func (f *notifierFlags) usesStdout() bool {
+	return slices.Contains(splitList(f.backends), "jsonl") && (f.jsonlFile == "" || f.jsonlFile == "-")
+}
+
+// newNotifier builds the backends selected with -notifier, fanning out to
+// them when there is more than one or routes are configured.
+func newNotifier(f *notifierFlags, logger *slog.Logger) (notify.Notifier, error) {
+	names := splitList(f.backends)
+	if len(names) == 0 {
+		return nil, fmt.Errorf("no notifier selected")
+	}
+	var routes []notify.Route
+	if f.routes != "" {
+		var err error
+		if routes, err = notify.LoadRoutes(f.routes); err != nil {
+			return nil, err
+		}
+	}
+
+	backends := map[string]notify.Notifier{}
+	for _, name := range names {
+		backend, err := newBackend(name, f, logger)
+		if err != nil {
+			return nil, err
+		}
+		backends[name] = backend
+	}
+	if len(backends) == 1 && len(routes) == 0 {
+		return backends[names[0]], nil
+	}
+	return notify.NewFanout(backends, routes, logger)
+}
+
+// newBackend builds a single notification backend. Secrets may come from
+// the environment so they stay out of process listings.
+func newBackend(name string, f *notifierFlags, logger *slog.Logger) (notify.Notifier, error) {
+	switch name {
+	case "desktop":
+		return notify.NewNotifier(logger)
+	case "slack":
+		return notify.NewSlackNotifier(notify.SlackConfig{
+			WebhookURL: envDefault(f.slackWebhook, "SLACK_WEBHOOK_URL"),
+			Token:      envDefault(f.slackToken, "SLACK_BOT_TOKEN"),
+			Channel:    f.slackChannel,
+		})
+	case "webhook":
+		return notify.NewWebhookNotifier(notify.WebhookConfig{
+			URL:         f.webhookURL,
+			Secret:      envDefault(f.webhookSecret, "WEBHOOK_SECRET"),
+			Headers:     f.webhookHeaders,
+			Timeout:     f.webhookTimeout,
+			MaxAttempts: f.webhookAttempts,
+		})
+	case "email":
+		return notify.NewEmailNotifier(notify.EmailConfig{
+			Host:             f.emailHost,
+			Port:             f.emailPort,
+			Security:         f.emailSecurity,
+			Username:         f.emailUsername,
+			Password:         envDefault(f.emailPassword, "SMTP_PASSWORD"),
+			From:             f.emailFrom,
+			To:               splitList(f.emailTo),
+			TextTemplateFile: f.emailTextTemplate,
+			HTMLTemplateFile: f.emailHTMLTemplate,
+		})
+	case "ntfy":
+		return notify.NewNtfyNotifier(notify.NtfyConfig{
+			Server: f.ntfyServer,
+			Topic:  f.ntfyTopic,
+			Token:  envDefault(f.ntfyToken, "NTFY_TOKEN"),
+		})
+	case "matrix":
+		return notify.NewMatrixNotifier(notify.MatrixConfig{
+			Homeserver: f.matrixHomeserver,
+			RoomID:     f.matrixRoom,
+			Token:      envDefault(f.matrixToken, "MATRIX_TOKEN"),
+			EditWindow: f.matrixEditWindow,
+		})
+	case "exec":
+		return notify.NewExecNotifier(notify.ExecConfig{
+			Command:     f.execCommand,
+			Timeout:     f.execTimeout,
+			Concurrency: f.execConcurrency,
+		}, logger)
+	case "jsonl":
+		return notify.NewJSONLNotifier(notify.JSONLConfig{
+			Path:       f.jsonlFile,
+			MaxSize:    int64(f.jsonlMaxMB) << 20,
+			MaxBackups: f.jsonlBackups,
+		}, logger)
+	default:
+		return nil, fmt.Errorf("unknown notifier %q", name)
+	}
+}
+
+func envDefault(value, key string) string {
+	if value != "" {
+		return value
+	}
+	return os.Getenv(key)
+}
@@ -12,18 +12,91 @@ import (
 type AuthoredRecord struct {
 	LastIssueComment time.Time `json:"last_issue_comment"`
 	LastReview       time.Time `json:"last_review"`
+	ReadyToMerge     bool      `json:"ready_to_merge,omitempty"`
+	// Comments and Reviews map each seen comment/review ID to a hash of its
+	// last seen body, so edits can be told apart from new activity.
+	Comments map[int64]string `json:"comments"`
+	Reviews  map[int64]string `json:"reviews"`
+	// ReviewerStates holds each reviewer's latest effective review state
+	// (APPROVED, CHANGES_REQUESTED or DISMISSED).
+	ReviewerStates map[string]string `json:"reviewer_states"`
+	// State is the last seen state (MERGED or CLOSED) of a watched PR once
+	// it is no longer open; watched PRs are not polled after that.
+	State string `json:"state,omitempty"`
+	// Watched marks a record kept for a PR watched by URL, so it can be
+	// dropped once the PR is unwatched.
+	Watched bool `json:"watched,omitempty"`
+	// AutoMerge and InMergeQueue record whether auto-merge was enabled and
+	// whether the PR was queued when last polled.
+	AutoMerge    bool `json:"auto_merge,omitempty"`
+	InMergeQueue bool `json:"in_merge_queue,omitempty"`
+	// ClosedAt is set while a merged or closed authored PR is kept tracked
+	// during the grace period; the record is pruned once it expires.
+	ClosedAt time.Time `json:"closed_at"`
+}
+
+// IssueRecord tracks an issue you opened or are assigned to.
+type IssueRecord struct {
+	Assigned bool `json:"assigned"`
+	// Watching is set once comments on the issue are being tracked, so that
+	// existing comments are not replayed when you are first assigned.
+	Watching    bool             `json:"watching"`
+	LastComment time.Time        `json:"last_comment"`
+	Comments    map[int64]string `json:"comments"`
+}
+
+// ThreadRecord tracks review thread resolution on a pull request.
+type ThreadRecord struct {
+	// Resolved maps each review thread ID to whether it was resolved.
+	Resolved map[string]bool `json:"resolved"`
+	// AllMineResolved is set once every thread you started is resolved, so
+	// the "all resolved" alert fires once per round of review.
+	AllMineResolved bool `json:"all_mine_resolved"`
 }
 
 type State struct {
 	Initialized bool                      `json:"initialized"`
 	AssignedPRs map[string]time.Time      `json:"assigned_prs"`
 	AuthoredPRs map[string]AuthoredRecord `json:"authored_prs"`
+	DraftPRs    map[string]bool           `json:"draft_prs"`
+	// AssignedHeads holds the head commit of each assigned PR when last
+	// seen, so pushed commits are noticed whatever their commit date.
+	AssignedHeads map[string]string      `json:"assigned_heads"`
+	Issues        map[string]IssueRecord `json:"issues"`
+	Labels        map[string][]string    `json:"labels"`
+	// Deployments holds the pending deployment approvals already notified,
+	// keyed by repo, run ID and environment.
+	Deployments map[string]bool `json:"deployments"`
+	// Workflows holds the last conclusion ("success" or "failure") of each
+	// watched default-branch workflow, keyed by repo and workflow ID.
+	Workflows map[string]string `json:"workflows"`
+	// RepoPRs holds the open pull requests seen in watched repositories and
+	// whether each was a draft when last seen.
+	RepoPRs map[string]bool `json:"repo_prs"`
+	// RepoHighWater holds the highest pull request number seen in each
+	// watched repository. Only PRs above it are reported as opened, so older
+	// PRs sliding into a truncated list are not mistaken for new ones.
+	RepoHighWater map[string]int          `json:"repo_high_water"`
+	Threads       map[string]ThreadRecord `json:"threads"`
+	// Pollers records which optional pollers have completed an initial sync,
+	// so enabling one later does not replay existing activity.
+	Pollers map[string]bool `json:"pollers"`
 }
 
 func NewState() *State {
 	return &State{
-		AssignedPRs: make(map[string]time.Time),
-		AuthoredPRs: make(map[string]AuthoredRecord),
+		AssignedPRs:   make(map[string]time.Time),
+		AuthoredPRs:   make(map[string]AuthoredRecord),
+		DraftPRs:      make(map[string]bool),
+		AssignedHeads: make(map[string]string),
+		Issues:        make(map[string]IssueRecord),
+		Labels:        make(map[string][]string),
+		Deployments:   make(map[string]bool),
+		Workflows:     make(map[string]string),
+		RepoPRs:       make(map[string]bool),
+		RepoHighWater: make(map[string]int),
+		Threads:       make(map[string]ThreadRecord),
+		Pollers:       make(map[string]bool),
 	}
 }
 
@@ -48,6 +121,36 @@ func Load(path string) (*State, error) {
 	if state.AuthoredPRs == nil {
 		state.AuthoredPRs = make(map[string]AuthoredRecord)
 	}
+	if state.DraftPRs == nil {
+		state.DraftPRs = make(map[string]bool)
+	}
+	if state.AssignedHeads == nil {
+		state.AssignedHeads = make(map[string]string)
+	}
+	if state.Issues == nil {
+		state.Issues = make(map[string]IssueRecord)
+	}
+	if state.Labels == nil {
+		state.Labels = make(map[string][]string)
+	}
+	if state.Deployments == nil {
+		state.Deployments = make(map[string]bool)
+	}
+	if state.Workflows == nil {
+		state.Workflows = make(map[string]string)
+	}
+	if state.RepoPRs == nil {
+		state.RepoPRs = make(map[string]bool)
+	}
+	if state.RepoHighWater == nil {
+		state.RepoHighWater = make(map[string]int)
+	}
+	if state.Threads == nil {
+		state.Threads = make(map[string]ThreadRecord)
+	}
+	if state.Pollers == nil {
+		state.Pollers = make(map[string]bool)
+	}
 	return &state, nil
 }
 
@@ -33,6 +33,8 @@ func TestSaveAndReload(t *testing.T) {
 	state.AuthoredPRs["org/repo#2"] = AuthoredRecord{
 		LastIssueComment: time.Unix(200, 0).UTC(),
 		LastReview:       time.Unix(300, 0).UTC(),
+		Comments:         map[int64]string{10: "abc"},
+		Reviews:          map[int64]string{20: "def"},
 	}
 
 	if err := Save(path, state); err != nil {
@@ -60,4 +62,7 @@ func TestSaveAndReload(t *testing.T) {
 	if got := reloaded.AuthoredPRs["org/repo#2"]; got.LastReview.IsZero() {
 		t.Errorf("AuthoredPR record missing: %#v", got)
 	}
+	if got := reloaded.AuthoredPRs["org/repo#2"]; got.Comments[10] != "abc" || got.Reviews[20] != "def" {
+		t.Errorf("seen comment/review hashes lost: %#v", got)
+	}
 }
@@ -0,0 +1,92 @@
+package cache
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// Subscriptions is the persisted list of pull requests watched by URL. It is
+// kept apart from State so the watch/unwatch commands can edit it while the
+// daemon is running.
+type Subscriptions struct {
+	PullRequests []string `json:"pull_requests"`
+}
+
+// Add appends url unless it is already present and reports whether the list
+// changed.
+func (s *Subscriptions) Add(url string) bool {
+	for _, existing := range s.PullRequests {
+		if existing == url {
+			return false
+		}
+	}
+	s.PullRequests = append(s.PullRequests, url)
+	return true
+}
+
+// Remove deletes url and reports whether it was present.
+func (s *Subscriptions) Remove(url string) bool {
+	for i, existing := range s.PullRequests {
+		if existing == url {
+			s.PullRequests = append(s.PullRequests[:i], s.PullRequests[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
+
+func LoadSubscriptions(path string) (*Subscriptions, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return &Subscriptions{}, nil
+		}
+		return nil, fmt.Errorf("read subscriptions: %w", err)
+	}
+	var subs Subscriptions
+	if len(data) == 0 {
+		return &subs, nil
+	}
+	if err := json.Unmarshal(data, &subs); err != nil {
+		return nil, fmt.Errorf("decode subscriptions: %w", err)
+	}
+	return &subs, nil
+}
+
+func SaveSubscriptions(path string, subs *Subscriptions) error {
+	if subs == nil {
+		return errors.New("subscriptions is nil")
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return fmt.Errorf("ensure subscriptions dir: %w", err)
+	}
+	data, err := json.MarshalIndent(subs, "", "  ")
+	if err != nil {
+		return fmt.Errorf("encode subscriptions: %w", err)
+	}
+	// The daemon reads the file while watch/unwatch rewrite it, so replace
+	// it in one step rather than truncating it in place.
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("create subscriptions temp file: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("write subscriptions: %w", err)
+	}
+	if err := tmp.Chmod(0o644); err != nil {
+		tmp.Close()
+		return fmt.Errorf("write subscriptions: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("write subscriptions: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return fmt.Errorf("replace subscriptions: %w", err)
+	}
+	return nil
+}
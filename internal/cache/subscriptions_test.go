@@ -0,0 +1,42 @@
+package cache
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func TestSubscriptionsRoundTrip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "subscriptions.json")
+
+	subs, err := LoadSubscriptions(path)
+	if err != nil {
+		t.Fatalf("LoadSubscriptions error = %v", err)
+	}
+	if len(subs.PullRequests) != 0 {
+		t.Fatalf("expected empty subscriptions, got %v", subs.PullRequests)
+	}
+
+	if !subs.Add("https://github.com/org/repo/pull/1") || !subs.Add("https://github.com/org/repo/pull/2") {
+		t.Fatal("expected Add to report a change")
+	}
+	if subs.Add("https://github.com/org/repo/pull/1") {
+		t.Error("duplicate Add reported a change")
+	}
+	if !subs.Remove("https://github.com/org/repo/pull/1") {
+		t.Error("Remove of present URL reported no change")
+	}
+	if subs.Remove("https://github.com/org/repo/pull/3") {
+		t.Error("Remove of absent URL reported a change")
+	}
+
+	if err := SaveSubscriptions(path, subs); err != nil {
+		t.Fatalf("SaveSubscriptions error = %v", err)
+	}
+	reloaded, err := LoadSubscriptions(path)
+	if err != nil {
+		t.Fatalf("LoadSubscriptions error = %v", err)
+	}
+	if len(reloaded.PullRequests) != 1 || reloaded.PullRequests[0] != "https://github.com/org/repo/pull/2" {
+		t.Fatalf("unexpected subscriptions after reload: %v", reloaded.PullRequests)
+	}
+}
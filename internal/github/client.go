@@ -4,7 +4,9 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
 	"log/slog"
 	"net/url"
 	"os"
@@ -38,6 +40,9 @@ type PullRequest struct {
 	Additions    int       `json:"additions"`
 	Deletions    int       `json:"deletions"`
 	ChangedFiles int       `json:"changedFiles"`
+	State        string    `json:"state"`
+	Author       User      `json:"author"`
+	HeadRefOid   string    `json:"headRefOid"`
 }
 
 type PullRequestSummary struct {
@@ -45,6 +50,25 @@ type PullRequestSummary struct {
 	Title     string    `json:"title"`
 	URL       string    `json:"url"`
 	UpdatedAt time.Time `json:"updatedAt"`
+	IsDraft   bool      `json:"isDraft"`
+	Labels    []Label   `json:"labels"`
+	Author    User      `json:"author"`
+	ClosedAt  time.Time `json:"closedAt"`
+}
+
+type Label struct {
+	Name string `json:"name"`
+}
+
+type IssueSummary struct {
+	Number    int       `json:"number"`
+	Title     string    `json:"title"`
+	URL       string    `json:"url"`
+	UpdatedAt time.Time `json:"updatedAt"`
+	Author    struct {
+		Login string `json:"login"`
+	} `json:"author"`
+	Assignees []User `json:"assignees"`
 }
 
 type IssueComment struct {
@@ -68,6 +92,203 @@ type Review struct {
 	HTMLURL string `json:"html_url"`
 }
 
+type User struct {
+	Login string `json:"login"`
+}
+
+type Team struct {
+	Slug string `json:"slug"`
+	Name string `json:"name"`
+}
+
+// RequestedReviewers lists the users and teams with a pending review request.
+type RequestedReviewers struct {
+	Users []User `json:"users"`
+	Teams []Team `json:"teams"`
+}
+
+// WorkflowRun is a GitHub Actions workflow run.
+type WorkflowRun struct {
+	ID         int64     `json:"id"`
+	WorkflowID int64     `json:"workflow_id"`
+	Name       string    `json:"name"`
+	HeadBranch string    `json:"head_branch"`
+	HeadSHA    string    `json:"head_sha"`
+	Status     string    `json:"status"`
+	Conclusion string    `json:"conclusion"`
+	HTMLURL    string    `json:"html_url"`
+	CreatedAt  time.Time `json:"created_at"`
+	Actor      User      `json:"actor"`
+	HeadCommit struct {
+		Message string `json:"message"`
+		Author  struct {
+			Name string `json:"name"`
+		} `json:"author"`
+	} `json:"head_commit"`
+}
+
+// PendingDeployment is an environment deployment awaiting review.
+type PendingDeployment struct {
+	Environment struct {
+		Name    string `json:"name"`
+		HTMLURL string `json:"html_url"`
+	} `json:"environment"`
+	CurrentUserCanApprove bool `json:"current_user_can_approve"`
+}
+
+// CheckStatus is one entry of a pull request's statusCheckRollup. Check runs
+// report Status and Conclusion; legacy commit statuses report State.
+type CheckStatus struct {
+	TypeName   string `json:"__typename"`
+	Name       string `json:"name"`
+	Context    string `json:"context"`
+	Status     string `json:"status"`
+	Conclusion string `json:"conclusion"`
+	State      string `json:"state"`
+}
+
+// MergeStatus captures the fields that decide whether a pull request can be
+// merged.
+type MergeStatus struct {
+	ReviewDecision    string        `json:"reviewDecision"`
+	Mergeable         string        `json:"mergeable"`
+	MergeStateStatus  string        `json:"mergeStateStatus"`
+	StatusCheckRollup []CheckStatus `json:"statusCheckRollup"`
+}
+
+// MergeQueueStatus describes a pull request's auto-merge and merge queue
+// state, along with the most recent removal from the queue and the last time
+// auto-merge was disabled.
+type MergeQueueStatus struct {
+	Title               string             `json:"title"`
+	URL                 string             `json:"url"`
+	State               string             `json:"state"`
+	MergedAt            time.Time          `json:"mergedAt"`
+	AutoMerge           *AutoMergeRequest  `json:"autoMergeRequest"`
+	QueueEntry          *MergeQueueEntry   `json:"mergeQueueEntry"`
+	LastRemoval         *MergeQueueRemoval `json:"-"`
+	AutoMergeDisabledAt time.Time          `json:"-"`
+}
+
+type AutoMergeRequest struct {
+	EnabledAt   time.Time `json:"enabledAt"`
+	MergeMethod string    `json:"mergeMethod"`
+	EnabledBy   User      `json:"enabledBy"`
+}
+
+type MergeQueueEntry struct {
+	State      string    `json:"state"`
+	Position   int       `json:"position"`
+	EnqueuedAt time.Time `json:"enqueuedAt"`
+}
+
+type MergeQueueRemoval struct {
+	CreatedAt time.Time `json:"createdAt"`
+	Reason    string    `json:"reason"`
+	Actor     User      `json:"actor"`
+}
+
+const mergeQueueStatusQuery = `query($owner: String!, $name: String!, $number: Int!) {
+  repository(owner: $owner, name: $name) {
+    pullRequest(number: $number) {
+      title
+      url
+      state
+      mergedAt
+      autoMergeRequest { enabledAt mergeMethod enabledBy { login } }
+      mergeQueueEntry { state position enqueuedAt }
+      timelineItems(last: 1, itemTypes: [REMOVED_FROM_MERGE_QUEUE_EVENT]) {
+        nodes { ... on RemovedFromMergeQueueEvent { createdAt reason actor { login } } }
+      }
+      autoMergeDisabled: timelineItems(last: 1, itemTypes: [AUTO_MERGE_DISABLED_EVENT]) {
+        nodes { ... on AutoMergeDisabledEvent { createdAt } }
+      }
+    }
+  }
+}`
+
+// ReviewThread is a pull request review conversation. Author, URL and Body
+// come from the thread's first comment.
+type ReviewThread struct {
+	ID         string `json:"id"`
+	IsResolved bool   `json:"isResolved"`
+	IsOutdated bool   `json:"isOutdated"`
+	Path       string `json:"path"`
+	ResolvedBy User   `json:"resolvedBy"`
+	Author     User   `json:"-"`
+	URL        string `json:"-"`
+	Body       string `json:"-"`
+}
+
+const reviewThreadsQuery = `query($owner: String!, $name: String!, $number: Int!) {
+  repository(owner: $owner, name: $name) {
+    pullRequest(number: $number) {
+      reviewThreads(first: 100) {
+        nodes {
+          id isResolved isOutdated path
+          resolvedBy { login }
+          comments(first: 1) { nodes { author { login } url body } }
+        }
+      }
+    }
+  }
+}`
+
+// TimelineEvent is a single entry from the issue timeline API. Only the fields
+// the monitor uses to classify activity are decoded.
+type TimelineEvent struct {
+	Event       string    `json:"event"`
+	CreatedAt   time.Time `json:"created_at"`
+	SubmittedAt time.Time `json:"submitted_at"`
+	Actor       struct {
+		Login string `json:"login"`
+	} `json:"actor"`
+	User struct {
+		Login string `json:"login"`
+	} `json:"user"`
+	// SHA identifies a committed event's commit; CommitID is the new head
+	// of a head_ref_force_pushed event.
+	SHA       string `json:"sha"`
+	CommitID  string `json:"commit_id"`
+	Committer struct {
+		Date time.Time `json:"date"`
+	} `json:"committer"`
+	Label struct {
+		Name string `json:"name"`
+	} `json:"label"`
+	Rename struct {
+		From string `json:"from"`
+		To   string `json:"to"`
+	} `json:"rename"`
+	RequestedReviewer struct {
+		Login string `json:"login"`
+	} `json:"requested_reviewer"`
+	RequestedTeam struct {
+		Slug string `json:"slug"`
+	} `json:"requested_team"`
+}
+
+// OccurredAt returns the event timestamp. Commits and reviews carry their
+// time in event-specific fields rather than created_at.
+func (e TimelineEvent) OccurredAt() time.Time {
+	switch {
+	case !e.CreatedAt.IsZero():
+		return e.CreatedAt
+	case !e.SubmittedAt.IsZero():
+		return e.SubmittedAt
+	default:
+		return e.Committer.Date
+	}
+}
+
+// ActorLogin returns the login responsible for the event, if known.
+func (e TimelineEvent) ActorLogin() string {
+	if e.Actor.Login != "" {
+		return e.Actor.Login
+	}
+	return e.User.Login
+}
+
 func (c *Client) CurrentUserLogin(ctx context.Context) (string, error) {
 	out, err := c.run(ctx, "api", "user", "--jq", ".login")
 	if err != nil {
@@ -80,12 +301,28 @@ func (c *Client) CurrentUserLogin(ctx context.Context) (string, error) {
 	return login, nil
 }
 
+// UserTeams lists the teams the authenticated user belongs to as "org/slug".
+// It needs the read:org scope.
+func (c *Client) UserTeams(ctx context.Context) ([]string, error) {
+	out, err := c.run(ctx, "api", "user/teams", "--paginate", "--jq", `.[] | .organization.login + "/" + .slug`)
+	if err != nil {
+		return nil, err
+	}
+	var teams []string
+	for _, line := range strings.Split(string(out), "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			teams = append(teams, line)
+		}
+	}
+	return teams, nil
+}
+
 func (c *Client) SearchAssignedPullRequests(ctx context.Context, query string, limit int) ([]PullRequestSummary, error) {
 	args := []string{"search", "prs"}
 	if trimmed := strings.TrimSpace(query); trimmed != "" {
 		args = append(args, strings.Fields(trimmed)...)
 	}
-	args = append(args, "--sort", "updated", "--order", "desc", "--json", "number,title,url,updatedAt")
+	args = append(args, "--sort", "updated", "--order", "desc", "--json", "number,title,url,updatedAt,isDraft,labels")
 	if limit > 0 {
 		args = append(args, "--limit", strconv.Itoa(limit))
 	}
@@ -101,7 +338,7 @@ func (c *Client) SearchAssignedPullRequests(ctx context.Context, query string, l
 }
 
 func (c *Client) ListAuthoredPullRequests(ctx context.Context, author string, limit int) ([]PullRequestSummary, error) {
-	args := []string{"search", "prs", "is:open", "is:pr", fmt.Sprintf("author:%s", author), "--sort", "updated", "--order", "desc", "--json", "number,title,url,updatedAt"}
+	args := []string{"search", "prs", "is:open", "is:pr", fmt.Sprintf("author:%s", author), "--sort", "updated", "--order", "desc", "--json", "number,title,url,updatedAt,labels"}
 	if limit > 0 {
 		args = append(args, "--limit", strconv.Itoa(limit))
 	}
@@ -119,11 +356,80 @@ func (c *Client) ListAuthoredPullRequests(ctx context.Context, author string, li
 	return prs, nil
 }
 
+func (c *Client) SearchIssues(ctx context.Context, query string, limit int) ([]IssueSummary, error) {
+	args := []string{"search", "issues"}
+	if trimmed := strings.TrimSpace(query); trimmed != "" {
+		args = append(args, strings.Fields(trimmed)...)
+	}
+	args = append(args, "--sort", "updated", "--order", "desc", "--json", "number,title,url,updatedAt,author,assignees")
+	if limit > 0 {
+		args = append(args, "--limit", strconv.Itoa(limit))
+	}
+	out, err := c.run(ctx, args...)
+	if err != nil {
+		return nil, err
+	}
+	var issues []IssueSummary
+	if len(bytes.TrimSpace(out)) == 0 {
+		return issues, nil
+	}
+	if err := json.Unmarshal(out, &issues); err != nil {
+		return nil, fmt.Errorf("decode issue search results: %w", err)
+	}
+	return issues, nil
+}
+
+func (c *Client) ListRepoPullRequests(ctx context.Context, repo string, limit int) ([]PullRequestSummary, error) {
+	args := []string{"pr", "list", "--repo", repo, "--state", "open", "--json", "number,title,url,updatedAt,isDraft,author"}
+	if limit > 0 {
+		args = append(args, "--limit", strconv.Itoa(limit))
+	}
+	out, err := c.run(ctx, args...)
+	if err != nil {
+		return nil, err
+	}
+	var prs []PullRequestSummary
+	if len(bytes.TrimSpace(out)) == 0 {
+		return prs, nil
+	}
+	if err := json.Unmarshal(out, &prs); err != nil {
+		return nil, fmt.Errorf("decode repository pull request list: %w", err)
+	}
+	return prs, nil
+}
+
+// ListClosedPullRequests returns pull requests by author closed or merged on
+// or after the day of since.
+func (c *Client) ListClosedPullRequests(ctx context.Context, author string, since time.Time, limit int) ([]PullRequestSummary, error) {
+	args := []string{
+		"search", "prs", "is:closed", "is:pr",
+		fmt.Sprintf("author:%s", author),
+		"closed:>=" + since.UTC().Format("2006-01-02"),
+		"--sort", "updated", "--order", "desc",
+		"--json", "number,title,url,updatedAt,labels,closedAt",
+	}
+	if limit > 0 {
+		args = append(args, "--limit", strconv.Itoa(limit))
+	}
+	out, err := c.run(ctx, args...)
+	if err != nil {
+		return nil, err
+	}
+	var prs []PullRequestSummary
+	if len(bytes.TrimSpace(out)) == 0 {
+		return prs, nil
+	}
+	if err := json.Unmarshal(out, &prs); err != nil {
+		return nil, fmt.Errorf("decode closed pull request list: %w", err)
+	}
+	return prs, nil
+}
+
 func (c *Client) PullRequestDetails(ctx context.Context, repo string, number int) (*PullRequest, error) {
 	args := []string{
 		"pr", "view", strconv.Itoa(number),
 		"--repo", repo,
-		"--json", "number,title,url,updatedAt,additions,deletions,changedFiles",
+		"--json", "number,title,url,updatedAt,additions,deletions,changedFiles,state,author,headRefOid",
 	}
 	out, err := c.run(ctx, args...)
 	if err != nil {
@@ -136,6 +442,115 @@ func (c *Client) PullRequestDetails(ctx context.Context, repo string, number int
 	return &pr, nil
 }
 
+func (c *Client) PullRequestMergeStatus(ctx context.Context, repo string, number int) (*MergeStatus, error) {
+	args := []string{
+		"pr", "view", strconv.Itoa(number),
+		"--repo", repo,
+		"--json", "reviewDecision,mergeable,mergeStateStatus,statusCheckRollup",
+	}
+	out, err := c.run(ctx, args...)
+	if err != nil {
+		return nil, err
+	}
+	var status MergeStatus
+	if err := json.Unmarshal(out, &status); err != nil {
+		return nil, fmt.Errorf("decode pull request merge status: %w", err)
+	}
+	return &status, nil
+}
+
+func (c *Client) RequestedReviewers(ctx context.Context, repo string, number int) (*RequestedReviewers, error) {
+	path := fmt.Sprintf("repos/%s/pulls/%d/requested_reviewers", repo, number)
+	out, err := c.run(ctx, "api", path, "--method", "GET")
+	if err != nil {
+		return nil, err
+	}
+	var reviewers RequestedReviewers
+	if len(bytes.TrimSpace(out)) == 0 {
+		return &reviewers, nil
+	}
+	if err := json.Unmarshal(out, &reviewers); err != nil {
+		return nil, fmt.Errorf("decode requested reviewers: %w", err)
+	}
+	return &reviewers, nil
+}
+
+func (c *Client) MergeQueueStatus(ctx context.Context, repo string, number int) (*MergeQueueStatus, error) {
+	var resp struct {
+		Data struct {
+			Repository struct {
+				PullRequest *struct {
+					MergeQueueStatus
+					TimelineItems struct {
+						Nodes []MergeQueueRemoval `json:"nodes"`
+					} `json:"timelineItems"`
+					AutoMergeDisabled struct {
+						Nodes []struct {
+							CreatedAt time.Time `json:"createdAt"`
+						} `json:"nodes"`
+					} `json:"autoMergeDisabled"`
+				} `json:"pullRequest"`
+			} `json:"repository"`
+		} `json:"data"`
+	}
+	if err := c.graphQL(ctx, mergeQueueStatusQuery, repo, number, &resp); err != nil {
+		return nil, fmt.Errorf("merge queue status: %w", err)
+	}
+	pr := resp.Data.Repository.PullRequest
+	if pr == nil {
+		return nil, fmt.Errorf("pull request %s#%d not found", repo, number)
+	}
+	status := pr.MergeQueueStatus
+	if nodes := pr.TimelineItems.Nodes; len(nodes) > 0 {
+		status.LastRemoval = &nodes[len(nodes)-1]
+	}
+	if nodes := pr.AutoMergeDisabled.Nodes; len(nodes) > 0 {
+		status.AutoMergeDisabledAt = nodes[len(nodes)-1].CreatedAt
+	}
+	return &status, nil
+}
+
+func (c *Client) ReviewThreads(ctx context.Context, repo string, number int) ([]ReviewThread, error) {
+	var resp struct {
+		Data struct {
+			Repository struct {
+				PullRequest *struct {
+					ReviewThreads struct {
+						Nodes []struct {
+							ReviewThread
+							Comments struct {
+								Nodes []struct {
+									Author User   `json:"author"`
+									URL    string `json:"url"`
+									Body   string `json:"body"`
+								} `json:"nodes"`
+							} `json:"comments"`
+						} `json:"nodes"`
+					} `json:"reviewThreads"`
+				} `json:"pullRequest"`
+			} `json:"repository"`
+		} `json:"data"`
+	}
+	if err := c.graphQL(ctx, reviewThreadsQuery, repo, number, &resp); err != nil {
+		return nil, fmt.Errorf("review threads: %w", err)
+	}
+	pr := resp.Data.Repository.PullRequest
+	if pr == nil {
+		return nil, fmt.Errorf("pull request %s#%d not found", repo, number)
+	}
+	threads := make([]ReviewThread, 0, len(pr.ReviewThreads.Nodes))
+	for _, node := range pr.ReviewThreads.Nodes {
+		thread := node.ReviewThread
+		if comments := node.Comments.Nodes; len(comments) > 0 {
+			thread.Author = comments[0].Author
+			thread.URL = comments[0].URL
+			thread.Body = comments[0].Body
+		}
+		threads = append(threads, thread)
+	}
+	return threads, nil
+}
+
 func (c *Client) IssueCommentsSince(ctx context.Context, repo string, number int, since time.Time) ([]IssueComment, error) {
 	path := fmt.Sprintf("repos/%s/issues/%d/comments", repo, number)
 	args := []string{"api", path, "--method", "GET", "-F", "per_page=100"}
@@ -173,6 +588,101 @@ func (c *Client) Reviews(ctx context.Context, repo string, number int) ([]Review
 	return reviews, nil
 }
 
+func (c *Client) PullRequestTimeline(ctx context.Context, repo string, number int) ([]TimelineEvent, error) {
+	path := fmt.Sprintf("repos/%s/issues/%d/timeline", repo, number)
+	args := []string{"api", path, "--method", "GET", "-F", "per_page=100", "--paginate", "--jq", ".[]"}
+	out, err := c.run(ctx, args...)
+	if err != nil {
+		return nil, err
+	}
+	events, err := decodeJSONLines[TimelineEvent](out)
+	if err != nil {
+		return nil, fmt.Errorf("decode pull request timeline: %w", err)
+	}
+	return events, nil
+}
+
+func (c *Client) WaitingWorkflowRuns(ctx context.Context, repo string) ([]WorkflowRun, error) {
+	return c.workflowRuns(ctx, repo, "-F", "status=waiting", "-F", "per_page=100")
+}
+
+// CompletedBranchWorkflowRuns lists the most recent completed push-triggered
+// workflow runs on branch, newest first.
+func (c *Client) CompletedBranchWorkflowRuns(ctx context.Context, repo, branch string) ([]WorkflowRun, error) {
+	return c.workflowRuns(ctx, repo, "-f", "branch="+branch, "-F", "event=push", "-F", "status=completed", "-F", "per_page=50")
+}
+
+func (c *Client) workflowRuns(ctx context.Context, repo string, params ...string) ([]WorkflowRun, error) {
+	path := fmt.Sprintf("repos/%s/actions/runs", repo)
+	args := append([]string{"api", path, "--method", "GET"}, params...)
+	out, err := c.run(ctx, args...)
+	if err != nil {
+		return nil, err
+	}
+	if len(bytes.TrimSpace(out)) == 0 {
+		return nil, nil
+	}
+	var page struct {
+		WorkflowRuns []WorkflowRun `json:"workflow_runs"`
+	}
+	if err := json.Unmarshal(out, &page); err != nil {
+		return nil, fmt.Errorf("decode workflow runs: %w", err)
+	}
+	return page.WorkflowRuns, nil
+}
+
+func (c *Client) DefaultBranch(ctx context.Context, repo string) (string, error) {
+	out, err := c.run(ctx, "api", "repos/"+repo, "--jq", ".default_branch")
+	if err != nil {
+		return "", err
+	}
+	branch := strings.TrimSpace(string(out))
+	if branch == "" {
+		return "", fmt.Errorf("gh returned empty default branch for %s", repo)
+	}
+	return branch, nil
+}
+
+func (c *Client) PendingDeployments(ctx context.Context, repo string, runID int64) ([]PendingDeployment, error) {
+	path := fmt.Sprintf("repos/%s/actions/runs/%d/pending_deployments", repo, runID)
+	out, err := c.run(ctx, "api", path, "--method", "GET")
+	if err != nil {
+		return nil, err
+	}
+	if len(bytes.TrimSpace(out)) == 0 {
+		return nil, nil
+	}
+	var deployments []PendingDeployment
+	if err := json.Unmarshal(out, &deployments); err != nil {
+		return nil, fmt.Errorf("decode pending deployments: %w", err)
+	}
+	return deployments, nil
+}
+
+// graphQL runs a query taking $owner, $name and $number variables for the
+// given pull request and decodes the response into v.
+func (c *Client) graphQL(ctx context.Context, query, repo string, number int, v any) error {
+	owner, name, ok := strings.Cut(repo, "/")
+	if !ok {
+		return fmt.Errorf("invalid repository %q", repo)
+	}
+	args := []string{
+		"api", "graphql",
+		"-f", "query=" + query,
+		"-f", "owner=" + owner,
+		"-f", "name=" + name,
+		"-F", "number=" + strconv.Itoa(number),
+	}
+	out, err := c.run(ctx, args...)
+	if err != nil {
+		return err
+	}
+	if err := json.Unmarshal(out, v); err != nil {
+		return fmt.Errorf("decode graphql response: %w", err)
+	}
+	return nil
+}
+
 func (c *Client) run(ctx context.Context, args ...string) ([]byte, error) {
 	cmd := exec.CommandContext(ctx, c.binary, args...)
 	cmd.Env = append(os.Environ(),
@@ -194,6 +704,23 @@ func (c *Client) run(ctx context.Context, args ...string) ([]byte, error) {
 	return stdout.Bytes(), nil
 }
 
+// decodeJSONLines decodes a stream of JSON values, as produced by
+// `gh api --paginate --jq '.[]'`.
+func decodeJSONLines[T any](data []byte) ([]T, error) {
+	var items []T
+	dec := json.NewDecoder(bytes.NewReader(data))
+	for {
+		var item T
+		if err := dec.Decode(&item); err != nil {
+			if errors.Is(err, io.EOF) {
+				return items, nil
+			}
+			return nil, err
+		}
+		items = append(items, item)
+	}
+}
+
 func RepoFromURL(raw string) (string, error) {
 	u, err := url.Parse(raw)
 	if err != nil {
@@ -205,3 +732,21 @@ func RepoFromURL(raw string) (string, error) {
 	}
 	return fmt.Sprintf("%s/%s", parts[0], parts[1]), nil
 }
+
+// PullRequestFromURL extracts the repository and number from a pull request
+// URL such as https://github.com/owner/repo/pull/123.
+func PullRequestFromURL(raw string) (string, int, error) {
+	u, err := url.Parse(raw)
+	if err != nil {
+		return "", 0, fmt.Errorf("parse url: %w", err)
+	}
+	parts := strings.Split(strings.Trim(u.Path, "/"), "/")
+	if len(parts) < 4 || parts[2] != "pull" {
+		return "", 0, fmt.Errorf("not a pull request url: %s", raw)
+	}
+	number, err := strconv.Atoi(parts[3])
+	if err != nil {
+		return "", 0, fmt.Errorf("invalid pull request number %q: %w", parts[3], err)
+	}
+	return fmt.Sprintf("%s/%s", parts[0], parts[1]), number, nil
+}
@@ -17,3 +17,52 @@ func TestRepoFromURLErrorsOnShortPath(t *testing.T) {
 		t.Fatalf("expected error for short path")
 	}
 }
+
+func TestDecodeJSONLines(t *testing.T) {
+	data := []byte(`{"event":"committed","committer":{"date":"2024-01-01T12:00:00Z"}}
+{"event":"labeled","created_at":"2024-01-01T13:00:00Z","label":{"name":"urgent"}}
+`)
+	events, err := decodeJSONLines[TimelineEvent](data)
+	if err != nil {
+		t.Fatalf("decodeJSONLines error = %v", err)
+	}
+	if len(events) != 2 {
+		t.Fatalf("expected 2 events, got %d", len(events))
+	}
+	if got := events[0].OccurredAt(); got.Hour() != 12 {
+		t.Errorf("commit OccurredAt = %v", got)
+	}
+	if events[1].Label.Name != "urgent" {
+		t.Errorf("label = %q", events[1].Label.Name)
+	}
+}
+
+func TestDecodeJSONLinesEmpty(t *testing.T) {
+	events, err := decodeJSONLines[TimelineEvent](nil)
+	if err != nil {
+		t.Fatalf("decodeJSONLines error = %v", err)
+	}
+	if len(events) != 0 {
+		t.Fatalf("expected no events, got %d", len(events))
+	}
+}
+
+func TestPullRequestFromURL(t *testing.T) {
+	repo, number, err := PullRequestFromURL("https://github.com/deseretdigital/example/pull/123/files")
+	if err != nil {
+		t.Fatalf("PullRequestFromURL error = %v", err)
+	}
+	if repo != "deseretdigital/example" || number != 123 {
+		t.Fatalf("unexpected result = %q, %d", repo, number)
+	}
+
+	for _, raw := range []string{
+		"https://github.com/deseretdigital/example/issues/123",
+		"https://github.com/deseretdigital/example/pull/abc",
+		"https://github.com/deseretdigital",
+	} {
+		if _, _, err := PullRequestFromURL(raw); err == nil {
+			t.Errorf("expected error for %q", raw)
+		}
+	}
+}
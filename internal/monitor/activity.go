@@ -0,0 +1,110 @@
+package monitor
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	githubapi "gh-review-notifier/internal/github"
+)
+
+// activity summarizes why an assigned pull request's updatedAt moved, derived
+// from its timeline.
+type activity struct {
+	requested bool
+	commits   int
+	comments  int
+	renamed   bool
+	labels    bool
+	// head is the PR's head commit according to the timeline.
+	head string
+}
+
+func (a activity) empty() bool {
+	return !a.requested && a.commits == 0 && a.comments == 0 && !a.renamed && !a.labels
+}
+
+func (a activity) describe() string {
+	var parts []string
+	if a.requested {
+		parts = append(parts, "Review requested")
+	}
+	if a.commits > 0 {
+		parts = append(parts, pluralize(a.commits, "new commit", "new commits"))
+	}
+	if a.comments > 0 {
+		parts = append(parts, pluralize(a.comments, "new comment", "new comments"))
+	}
+	if a.renamed {
+		parts = append(parts, "Title changed")
+	}
+	if a.labels {
+		parts = append(parts, "Labels changed")
+	}
+	return strings.Join(parts, ", ")
+}
+
+// classifyActivity inspects timeline events newer than since and records the
+// kinds of change they represent. Events performed by self are ignored, as are
+// event types that carry no review-relevant signal (description edits,
+// subscriptions, cross references and the like). Team review requests count
+// only for teams myTeam accepts.
+//
+// Commits are dated when they were made, not when they were pushed, so when
+// lastHead is found in the timeline the commits after it are counted instead.
+func classifyActivity(events []githubapi.TimelineEvent, since time.Time, lastHead, self string, myTeam func(slug string) bool) activity {
+	var a activity
+	headAt := -1
+	for i, ev := range events {
+		switch {
+		case ev.Event == "committed" && ev.SHA != "":
+			a.head = ev.SHA
+			if lastHead != "" && ev.SHA == lastHead {
+				headAt = i
+			}
+		case ev.Event == "head_ref_force_pushed" && ev.CommitID != "":
+			a.head = ev.CommitID
+		}
+	}
+
+	for i, ev := range events {
+		if ev.Event == "committed" {
+			if (headAt >= 0 && i > headAt) || (headAt < 0 && ev.OccurredAt().After(since)) {
+				a.commits++
+			}
+			continue
+		}
+		if !ev.OccurredAt().After(since) {
+			continue
+		}
+		if ev.Event == "review_requested" {
+			if slug := ev.RequestedTeam.Slug; slug != "" {
+				a.requested = a.requested || myTeam(slug)
+			} else if strings.EqualFold(ev.RequestedReviewer.Login, self) {
+				a.requested = true
+			}
+			continue
+		}
+		if self != "" && strings.EqualFold(ev.ActorLogin(), self) {
+			continue
+		}
+		switch ev.Event {
+		case "head_ref_force_pushed":
+			a.commits++
+		case "commented", "reviewed", "line-commented":
+			a.comments++
+		case "renamed":
+			a.renamed = true
+		case "labeled", "unlabeled":
+			a.labels = true
+		}
+	}
+	return a
+}
+
+func pluralize(n int, singular, plural string) string {
+	if n == 1 {
+		return fmt.Sprintf("%d %s", n, singular)
+	}
+	return fmt.Sprintf("%d %s", n, plural)
+}
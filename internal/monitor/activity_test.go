@@ -0,0 +1,96 @@
+package monitor
+
+import (
+	"testing"
+	"time"
+
+	githubapi "gh-review-notifier/internal/github"
+)
+
+func TestClassifyActivity(t *testing.T) {
+	since := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	after := since.Add(time.Minute)
+
+	requested := githubapi.TimelineEvent{Event: "review_requested", CreatedAt: after}
+	requested.RequestedReviewer.Login = "Me"
+
+	otherRequested := githubapi.TimelineEvent{Event: "review_requested", CreatedAt: after}
+	otherRequested.RequestedReviewer.Login = "someone"
+
+	comment := githubapi.TimelineEvent{Event: "commented", CreatedAt: after}
+	comment.Actor.Login = "teammate"
+
+	ownComment := githubapi.TimelineEvent{Event: "commented", CreatedAt: after}
+	ownComment.Actor.Login = "me"
+
+	oldComment := githubapi.TimelineEvent{Event: "commented", CreatedAt: since}
+	oldComment.Actor.Login = "teammate"
+
+	events := []githubapi.TimelineEvent{
+		requested,
+		otherRequested,
+		comment,
+		ownComment,
+		oldComment,
+		commitEvent(after),
+		{Event: "renamed", CreatedAt: after},
+		{Event: "labeled", CreatedAt: after},
+		{Event: "mentioned", CreatedAt: after},
+	}
+
+	got := classifyActivity(events, since, "", "me", anyTeam)
+	want := activity{requested: true, commits: 1, comments: 1, renamed: true, labels: true}
+	if got != want {
+		t.Fatalf("classifyActivity = %+v, want %+v", got, want)
+	}
+	if desc := got.describe(); desc != "Review requested, 1 new commit, 1 new comment, Title changed, Labels changed" {
+		t.Errorf("describe = %q", desc)
+	}
+}
+
+func TestClassifyActivityIgnoresNoise(t *testing.T) {
+	since := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	events := []githubapi.TimelineEvent{
+		{Event: "subscribed", CreatedAt: since.Add(time.Minute)},
+		{Event: "mentioned", CreatedAt: since.Add(time.Minute)},
+	}
+	if got := classifyActivity(events, since, "", "me", anyTeam); !got.empty() {
+		t.Fatalf("expected empty activity, got %+v", got)
+	}
+}
+
+func anyTeam(string) bool { return true }
+
+func TestClassifyActivityCountsPushedCommits(t *testing.T) {
+	since := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	before := since.Add(-time.Hour)
+
+	known := commitEvent(before.Add(-time.Hour))
+	known.SHA = "aaa"
+	// Committed locally before the last poll, pushed after it.
+	pushed := commitEvent(before)
+	pushed.SHA = "bbb"
+
+	got := classifyActivity([]githubapi.TimelineEvent{known, pushed}, since, "aaa", "me", anyTeam)
+	if got.commits != 1 || got.head != "bbb" {
+		t.Fatalf("classifyActivity = %+v, want 1 commit and head bbb", got)
+	}
+	if got := classifyActivity([]githubapi.TimelineEvent{known, pushed}, since, "bbb", "me", anyTeam); got.commits != 0 {
+		t.Errorf("expected no commits after the known head, got %+v", got)
+	}
+}
+
+func TestClassifyActivityIgnoresOtherTeams(t *testing.T) {
+	since := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	requested := githubapi.TimelineEvent{Event: "review_requested", CreatedAt: since.Add(time.Minute)}
+	requested.RequestedTeam.Slug = "infra"
+
+	mine := func(slug string) bool { return slug == "web" }
+	if got := classifyActivity([]githubapi.TimelineEvent{requested}, since, "", "me", mine); got.requested {
+		t.Errorf("a request to another team should not count")
+	}
+	requested.RequestedTeam.Slug = "web"
+	if got := classifyActivity([]githubapi.TimelineEvent{requested}, since, "", "me", mine); !got.requested {
+		t.Errorf("a request to the user's team should count")
+	}
+}
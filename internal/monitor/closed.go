@@ -0,0 +1,62 @@
+package monitor
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	githubapi "gh-review-notifier/internal/github"
+)
+
+// pollRecentlyClosed keeps notifying about comments and reviews on authored
+// PRs merged or closed within the grace period, and prunes records for PRs
+// closed before it. When open holds every open authored PR, records of PRs
+// that are neither open nor recently closed, such as ones closed while the
+// daemon was not running, are pruned too.
+func (m *Monitor) pollRecentlyClosed(ctx context.Context, open map[string]bool) error {
+	cutoff := time.Now().Add(-m.cfg.ClosedGracePeriod)
+	results, err := m.client.ListClosedPullRequests(ctx, m.cfg.Author, cutoff, m.cfg.MaxResults)
+	if err != nil {
+		return fmt.Errorf("list closed authored PRs: %w", err)
+	}
+	closed := make(map[string]bool, len(results))
+	for _, item := range results {
+		if item.ClosedAt.IsZero() || item.ClosedAt.Before(cutoff) {
+			continue
+		}
+		repo, err := githubapi.RepoFromURL(item.URL)
+		if err != nil {
+			m.logger.Warn("failed to resolve repo from URL", slog.String("url", item.URL), slog.String("error", err.Error()))
+			continue
+		}
+		key := prKey(repo, item.Number)
+		closed[key] = true
+
+		m.mu.Lock()
+		record, known := m.state.AuthoredPRs[key]
+		record.ClosedAt = item.ClosedAt
+		m.state.AuthoredPRs[key] = record
+		m.mu.Unlock()
+
+		m.trackPullRequest(ctx, repo, item, !known)
+	}
+	complete := open != nil && len(results) < m.cfg.MaxResults
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for key, record := range m.state.AuthoredPRs {
+		switch {
+		case !record.ClosedAt.IsZero():
+			if !record.ClosedAt.Before(cutoff) {
+				continue
+			}
+		case !complete || record.Watched || record.State != "" || open[key] || closed[key]:
+			continue
+		}
+		delete(m.state.AuthoredPRs, key)
+		delete(m.state.Labels, key)
+		delete(m.state.Threads, key)
+	}
+	return nil
+}
@@ -0,0 +1,133 @@
+package monitor
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"gh-review-notifier/internal/cache"
+	githubapi "gh-review-notifier/internal/github"
+)
+
+func TestPollRecentlyClosedNotifiesComments(t *testing.T) {
+	ctx := context.Background()
+	state := cache.NewState()
+	state.Initialized = true
+
+	key := "deseretdigital/example#500"
+	state.AuthoredPRs[key] = cache.AuthoredRecord{
+		Comments: map[int64]string{1: bodyHash("Looks good")},
+		Reviews:  map[int64]string{},
+	}
+	seen := githubapi.IssueComment{ID: 1, Body: "Looks good", UpdatedAt: time.Now().Add(-2 * time.Hour)}
+	seen.User.Login = "lead"
+	followUp := githubapi.IssueComment{ID: 2, Body: "This broke staging", UpdatedAt: time.Now().Add(-time.Minute)}
+	followUp.User.Login = "lead"
+
+	client := &fakeGitHubClient{
+		closed: []githubapi.PullRequestSummary{{
+			Number:   500,
+			Title:    "Ship it",
+			URL:      "https://github.com/deseretdigital/example/pull/500",
+			ClosedAt: time.Now().Add(-time.Hour),
+		}},
+		issueComments: map[string][]githubapi.IssueComment{
+			key: {seen, followUp},
+		},
+	}
+
+	notifier := &fakeNotifier{}
+	mon := NewMonitor(Config{Author: "trixtur", ClosedGracePeriod: 24 * time.Hour}, client, notifier, state, nil)
+	if err := mon.pollRecentlyClosed(ctx, nil); err != nil {
+		t.Fatalf("pollRecentlyClosed error = %v", err)
+	}
+	if len(notifier.notifications) != 1 {
+		t.Fatalf("expected 1 notification, got %d", len(notifier.notifications))
+	}
+	record, ok := state.AuthoredPRs[key]
+	if !ok || record.ClosedAt.IsZero() {
+		t.Fatalf("expected closed PR to stay tracked with ClosedAt, got %+v", record)
+	}
+}
+
+func TestPollRecentlyClosedPrunesExpired(t *testing.T) {
+	ctx := context.Background()
+	state := cache.NewState()
+	state.Initialized = true
+
+	expired := "deseretdigital/example#501"
+	open := "deseretdigital/example#502"
+	state.AuthoredPRs[expired] = cache.AuthoredRecord{ClosedAt: time.Now().Add(-48 * time.Hour)}
+	unknown := "deseretdigital/example#503"
+	watched := "deseretdigital/example#504"
+	state.AuthoredPRs[open] = cache.AuthoredRecord{}
+	state.AuthoredPRs[unknown] = cache.AuthoredRecord{}
+	state.AuthoredPRs[watched] = cache.AuthoredRecord{Watched: true}
+	state.Labels[expired] = []string{"bug"}
+
+	notifier := &fakeNotifier{}
+	mon := NewMonitor(Config{Author: "trixtur", MaxResults: 10, ClosedGracePeriod: 24 * time.Hour}, &fakeGitHubClient{}, notifier, state, nil)
+	if err := mon.pollRecentlyClosed(ctx, map[string]bool{open: true}); err != nil {
+		t.Fatalf("pollRecentlyClosed error = %v", err)
+	}
+	if _, ok := state.AuthoredPRs[expired]; ok {
+		t.Errorf("expected expired PR to be pruned")
+	}
+	if _, ok := state.Labels[expired]; ok {
+		t.Errorf("expected expired PR labels to be pruned")
+	}
+	if _, ok := state.AuthoredPRs[open]; !ok {
+		t.Errorf("expected open PR to be kept")
+	}
+	if _, ok := state.AuthoredPRs[unknown]; ok {
+		t.Errorf("expected PR closed without a recorded ClosedAt to be pruned")
+	}
+	if _, ok := state.AuthoredPRs[watched]; !ok {
+		t.Errorf("expected watched PR to be kept")
+	}
+}
+
+func TestPollAuthoredKeepsReopenedPR(t *testing.T) {
+	ctx := context.Background()
+	state := cache.NewState()
+	state.Initialized = true
+
+	key := "deseretdigital/example#505"
+	state.AuthoredPRs[key] = cache.AuthoredRecord{
+		Comments: map[int64]string{1: bodyHash("Old news")},
+		Reviews:  map[int64]string{},
+		ClosedAt: time.Now().Add(-time.Hour),
+	}
+	old := githubapi.IssueComment{ID: 1, Body: "Old news", UpdatedAt: time.Now().Add(-2 * time.Hour)}
+	old.User.Login = "lead"
+
+	client := &fakeGitHubClient{
+		authored: []githubapi.PullRequestSummary{{
+			Number: 505,
+			Title:  "Try again",
+			URL:    "https://github.com/deseretdigital/example/pull/505",
+		}},
+		issueComments: map[string][]githubapi.IssueComment{key: {old}},
+	}
+
+	notifier := &fakeNotifier{}
+	mon := NewMonitor(Config{Author: "trixtur", MaxResults: 10, ClosedGracePeriod: 24 * time.Hour}, client, notifier, state, nil)
+	if err := mon.pollAuthored(ctx); err != nil {
+		t.Fatalf("pollAuthored error = %v", err)
+	}
+	if record := state.AuthoredPRs[key]; !record.ClosedAt.IsZero() {
+		t.Fatalf("ClosedAt kept after reopen: %v", record.ClosedAt)
+	}
+
+	// Once the original grace period would have expired, the record stays.
+	mon.cfg.ClosedGracePeriod = time.Minute
+	if err := mon.pollAuthored(ctx); err != nil {
+		t.Fatalf("pollAuthored error = %v", err)
+	}
+	if _, ok := state.AuthoredPRs[key]; !ok {
+		t.Fatal("reopened PR was pruned")
+	}
+	if len(notifier.notifications) != 0 {
+		t.Fatalf("expected no notifications, got %+v", notifier.notifications)
+	}
+}
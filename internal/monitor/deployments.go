@@ -0,0 +1,107 @@
+package monitor
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+
+	"gh-review-notifier/internal/notify"
+)
+
+const deploymentsPoller = "deployments"
+
+// pollDeployments notifies once per workflow run and environment when a
+// deployment in a watched repository is waiting for an approval you are
+// allowed to give.
+func (m *Monitor) pollDeployments(ctx context.Context) error {
+	seeding := m.seeding(deploymentsPoller)
+
+	for _, repo := range m.cfg.DeploymentRepos {
+		runs, err := m.client.WaitingWorkflowRuns(ctx, repo)
+		if err != nil {
+			m.logger.Warn("waiting workflow runs fetch failed", slog.String("repo", repo), slog.String("error", err.Error()))
+			continue
+		}
+
+		current := make(map[string]bool)
+		for _, run := range runs {
+			pending, err := m.client.PendingDeployments(ctx, repo, run.ID)
+			if err != nil {
+				m.logger.Warn("pending deployments fetch failed", slog.String("repo", repo), slog.Int64("run", run.ID), slog.String("error", err.Error()))
+				m.keepDeployments(current, deploymentKey(repo, run.ID, ""))
+				continue
+			}
+			for _, deployment := range pending {
+				if !deployment.CurrentUserCanApprove {
+					continue
+				}
+				env := deployment.Environment.Name
+				key := deploymentKey(repo, run.ID, env)
+				current[key] = true
+
+				m.mu.Lock()
+				notified := m.state.Deployments[key]
+				m.state.Deployments[key] = true
+				m.mu.Unlock()
+				if notified || seeding {
+					continue
+				}
+
+				summary := fmt.Sprintf("%s · %s", run.HeadBranch, shortSHA(run.HeadSHA))
+				if run.Actor.Login != "" {
+					summary = fmt.Sprintf("%s · %s", run.Actor.Login, summary)
+				}
+				event := notify.Event{
+					Kind:       notify.KindDeployment,
+					Repo:       repo,
+					Title:      fmt.Sprintf("Deployment to %s awaiting approval", env),
+					Context:    run.Name,
+					Summary:    summary,
+					Actor:      run.Actor.Login,
+					URL:        run.HTMLURL,
+					OccurredAt: run.CreatedAt,
+					DedupeKey:  dedupeKey(notify.KindDeployment, key),
+				}
+				if err := m.notifier.Notify(ctx, event); err != nil {
+					m.logger.Warn("notification failed", slog.String("repo", repo), slog.Int64("run", run.ID), slog.String("error", err.Error()))
+				}
+			}
+		}
+
+		// Forget approvals that are no longer pending for this repository.
+		m.mu.Lock()
+		for key := range m.state.Deployments {
+			if strings.HasPrefix(key, repo+"/runs/") && !current[key] {
+				delete(m.state.Deployments, key)
+			}
+		}
+		m.mu.Unlock()
+	}
+
+	m.markSeeded(deploymentsPoller)
+	return nil
+}
+
+// keepDeployments marks cached entries under prefix as still current, used when
+// a run's pending deployments could not be fetched.
+func (m *Monitor) keepDeployments(current map[string]bool, prefix string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for key := range m.state.Deployments {
+		if strings.HasPrefix(key, prefix) {
+			current[key] = true
+		}
+	}
+}
+
+func deploymentKey(repo string, runID int64, env string) string {
+	return fmt.Sprintf("%s/runs/%d/%s", repo, runID, env)
+}
+
+func shortSHA(sha string) string {
+	if len(sha) > 7 {
+		return sha[:7]
+	}
+	return sha
+}
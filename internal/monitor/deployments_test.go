@@ -0,0 +1,89 @@
+package monitor
+
+import (
+	"context"
+	"testing"
+
+	"gh-review-notifier/internal/cache"
+	githubapi "gh-review-notifier/internal/github"
+)
+
+func pendingDeployment(env string, canApprove bool) githubapi.PendingDeployment {
+	var deployment githubapi.PendingDeployment
+	deployment.Environment.Name = env
+	deployment.CurrentUserCanApprove = canApprove
+	return deployment
+}
+
+func TestPollDeploymentsNotifiesOncePerApproval(t *testing.T) {
+	ctx := context.Background()
+	state := cache.NewState()
+	state.Initialized = true
+	state.Pollers[deploymentsPoller] = true
+
+	run := githubapi.WorkflowRun{
+		ID:         555,
+		Name:       "Deploy",
+		HeadBranch: "main",
+		HeadSHA:    "0123456789abcdef",
+		HTMLURL:    "https://github.com/deseretdigital/example/actions/runs/555",
+		Actor:      githubapi.User{Login: "releaser"},
+	}
+	client := &fakeGitHubClient{
+		waitingRuns: map[string][]githubapi.WorkflowRun{"deseretdigital/example": {run}},
+		pendingDeploy: map[int64][]githubapi.PendingDeployment{
+			555: {pendingDeployment("production", true), pendingDeployment("staging", false)},
+		},
+	}
+
+	notifier := &fakeNotifier{}
+	mon := NewMonitor(Config{DeploymentRepos: []string{"deseretdigital/example"}}, client, notifier, state, nil)
+
+	for i := 0; i < 2; i++ {
+		if err := mon.pollDeployments(ctx); err != nil {
+			t.Fatalf("pollDeployments error = %v", err)
+		}
+	}
+
+	want := notification{
+		title:    "Deployment to production awaiting approval",
+		subtitle: "deseretdigital/example · Deploy",
+		message:  "releaser · main · 0123456",
+		link:     "https://github.com/deseretdigital/example/actions/runs/555",
+	}
+	if len(notifier.notifications) != 1 || notifier.notifications[0] != want {
+		t.Fatalf("notifications = %+v, want [%+v]", notifier.notifications, want)
+	}
+
+	client.waitingRuns = nil
+	if err := mon.pollDeployments(ctx); err != nil {
+		t.Fatalf("pollDeployments error = %v", err)
+	}
+	if len(state.Deployments) != 0 {
+		t.Errorf("expected resolved approvals to be pruned, got %v", state.Deployments)
+	}
+}
+
+func TestPollDeploymentsSeedsWhenFirstEnabled(t *testing.T) {
+	state := cache.NewState()
+	state.Initialized = true
+
+	client := &fakeGitHubClient{
+		waitingRuns: map[string][]githubapi.WorkflowRun{"deseretdigital/example": {{ID: 1, Name: "Deploy"}}},
+		pendingDeploy: map[int64][]githubapi.PendingDeployment{
+			1: {pendingDeployment("production", true)},
+		},
+	}
+
+	notifier := &fakeNotifier{}
+	mon := NewMonitor(Config{DeploymentRepos: []string{"deseretdigital/example"}}, client, notifier, state, nil)
+	if err := mon.pollDeployments(context.Background()); err != nil {
+		t.Fatalf("pollDeployments error = %v", err)
+	}
+	if len(notifier.notifications) != 0 {
+		t.Fatalf("expected no notifications while seeding, got %d", len(notifier.notifications))
+	}
+	if !state.Pollers[deploymentsPoller] || !state.Deployments[deploymentKey("deseretdigital/example", 1, "production")] {
+		t.Errorf("seed state not recorded: %+v", state)
+	}
+}
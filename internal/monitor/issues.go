@@ -0,0 +1,129 @@
+package monitor
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+
+	"gh-review-notifier/internal/cache"
+	githubapi "gh-review-notifier/internal/github"
+	"gh-review-notifier/internal/notify"
+)
+
+const issuesPoller = "issues"
+
+// pollIssues notifies when a matching issue is newly assigned to you and when
+// others comment on issues you opened or are assigned to.
+func (m *Monitor) pollIssues(ctx context.Context) error {
+	results, err := m.client.SearchIssues(ctx, m.cfg.IssueQuery, m.cfg.MaxResults)
+	if err != nil {
+		return fmt.Errorf("search issues: %w", err)
+	}
+	seeding := m.seeding(issuesPoller)
+
+	seen := make(map[string]bool, len(results))
+	for _, item := range results {
+		repo, err := githubapi.RepoFromURL(item.URL)
+		if err != nil {
+			m.logger.Warn("failed to resolve repo from URL", slog.String("url", item.URL), slog.String("error", err.Error()))
+			continue
+		}
+		key := prKey(repo, item.Number)
+		seen[key] = true
+
+		m.mu.Lock()
+		record := m.state.Issues[key]
+		m.mu.Unlock()
+		if record.Comments == nil {
+			record.Comments = make(map[int64]string)
+		}
+
+		assigned := m.isSelf(item.Assignees)
+		if assigned && !record.Assigned && !seeding {
+			summary := "Assigned to you"
+			if author := item.Author.Login; author != "" {
+				summary = fmt.Sprintf("Assigned to you · opened by %s", author)
+			}
+			event := notify.Event{
+				Kind:       notify.KindIssueAssigned,
+				Repo:       repo,
+				Number:     item.Number,
+				Title:      item.Title,
+				Summary:    summary,
+				Actor:      item.Author.Login,
+				URL:        item.URL,
+				OccurredAt: item.UpdatedAt,
+				DedupeKey:  dedupeKey(notify.KindIssueAssigned, key, item.UpdatedAt.Unix()),
+			}
+			if err := m.notifier.Notify(ctx, event); err != nil {
+				m.logger.Warn("notification failed", slog.String("repo", repo), slog.Int("number", item.Number), slog.String("error", err.Error()))
+			}
+		}
+		record.Assigned = assigned
+
+		authored := m.cfg.Author != "" && strings.EqualFold(item.Author.Login, m.cfg.Author)
+		if assigned || authored {
+			if m.pollIssueComments(ctx, repo, item, &record, seeding || !record.Watching) {
+				record.Watching = true
+			}
+		}
+
+		m.mu.Lock()
+		m.state.Issues[key] = record
+		m.mu.Unlock()
+	}
+
+	// Forget issues that no longer match, unless the search may have been
+	// truncated.
+	if len(results) < m.cfg.MaxResults {
+		m.mu.Lock()
+		for key := range m.state.Issues {
+			if !seen[key] {
+				delete(m.state.Issues, key)
+			}
+		}
+		m.mu.Unlock()
+	}
+
+	m.markSeeded(issuesPoller)
+	return nil
+}
+
+// pollIssueComments notifies on new comments by others since the last poll,
+// or only records them when seeding. It reports whether comments were fetched.
+func (m *Monitor) pollIssueComments(ctx context.Context, repo string, item githubapi.IssueSummary, record *cache.IssueRecord, seeding bool) bool {
+	comments, err := m.client.IssueCommentsSince(ctx, repo, item.Number, record.LastComment)
+	if err != nil {
+		m.logger.Warn("issue comments fetch failed", slog.String("repo", repo), slog.Int("number", item.Number), slog.String("error", err.Error()))
+		return false
+	}
+	for _, cmt := range comments {
+		if cmt.UpdatedAt.After(record.LastComment) {
+			record.LastComment = cmt.UpdatedAt
+		}
+		change := trackSeen(record.Comments, cmt.ID, cmt.Body)
+		if seeding || !m.shouldNotifySeen(change) {
+			continue
+		}
+		if m.cfg.Author != "" && strings.EqualFold(cmt.User.Login, m.cfg.Author) {
+			continue
+		}
+		if err := m.notifier.Notify(ctx, commentEvent(repo, item.Number, item.Title, cmt, change)); err != nil {
+			m.logger.Warn("notification failed", slog.String("repo", repo), slog.Int("number", item.Number), slog.String("error", err.Error()))
+		}
+	}
+	return true
+}
+
+func (m *Monitor) isSelf(users []githubapi.User) bool {
+	if m.cfg.Author == "" {
+		return false
+	}
+	for _, user := range users {
+		if strings.EqualFold(user.Login, m.cfg.Author) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,108 @@
+package monitor
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"gh-review-notifier/internal/cache"
+	githubapi "gh-review-notifier/internal/github"
+)
+
+func TestPollIssuesAssignmentAndComments(t *testing.T) {
+	ctx := context.Background()
+	state := cache.NewState()
+	state.Initialized = true
+
+	updated := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	opened := githubapi.IssueSummary{
+		Number:    7,
+		Title:     "Flaky deploys",
+		URL:       "https://github.com/deseretdigital/example/issues/7",
+		UpdatedAt: updated,
+	}
+	opened.Author.Login = "trixtur"
+
+	other := githubapi.IssueSummary{
+		Number:    8,
+		Title:     "Docs typo",
+		URL:       "https://github.com/deseretdigital/example/issues/8",
+		UpdatedAt: updated,
+	}
+	other.Author.Login = "someone"
+
+	existing := githubapi.IssueComment{ID: 1, Body: "Seen this too", UpdatedAt: updated}
+	existing.User.Login = "teammate"
+
+	client := &fakeGitHubClient{
+		issues: []githubapi.IssueSummary{opened, other},
+		issueComments: map[string][]githubapi.IssueComment{
+			"deseretdigital/example#7": {existing},
+			"deseretdigital/example#8": {existing},
+		},
+	}
+
+	notifier := &fakeNotifier{}
+	mon := NewMonitor(Config{Author: "trixtur", IssueQuery: "is:issue involves:@me"}, client, notifier, state, nil)
+
+	// The first run after enabling issue monitoring only seeds the cache.
+	if err := mon.pollIssues(ctx); err != nil {
+		t.Fatalf("pollIssues error = %v", err)
+	}
+	if len(notifier.notifications) != 0 {
+		t.Fatalf("expected no notifications while seeding, got %d", len(notifier.notifications))
+	}
+
+	newComment := githubapi.IssueComment{ID: 2, Body: "Fixed by #9?", UpdatedAt: updated.Add(time.Hour)}
+	newComment.User.Login = "teammate"
+	ownComment := githubapi.IssueComment{ID: 3, Body: "Looking", UpdatedAt: updated.Add(time.Hour)}
+	ownComment.User.Login = "trixtur"
+	client.issueComments["deseretdigital/example#7"] = []githubapi.IssueComment{existing, newComment, ownComment}
+	client.issueComments["deseretdigital/example#8"] = []githubapi.IssueComment{existing, newComment}
+	client.issues[1].Assignees = []githubapi.User{{Login: "Trixtur"}}
+
+	if err := mon.pollIssues(ctx); err != nil {
+		t.Fatalf("pollIssues error = %v", err)
+	}
+
+	want := []notification{
+		{title: "Flaky deploys", subtitle: "deseretdigital/example · #7", message: "teammate: Fixed by #9?"},
+		{title: "Docs typo", subtitle: "deseretdigital/example · #8", message: "Assigned to you · opened by someone", link: "https://github.com/deseretdigital/example/issues/8"},
+	}
+	if len(notifier.notifications) != len(want) {
+		t.Fatalf("notifications = %+v, want %+v", notifier.notifications, want)
+	}
+	for i := range want {
+		if notifier.notifications[i] != want[i] {
+			t.Errorf("notification %d = %+v, want %+v", i, notifier.notifications[i], want[i])
+		}
+	}
+	if record := state.Issues["deseretdigital/example#8"]; !record.Assigned || !record.Watching {
+		t.Errorf("assignment not recorded: %+v", record)
+	}
+
+	// Comments that predate the assignment are not replayed; later ones are.
+	later := githubapi.IssueComment{ID: 4, Body: "Thanks!", UpdatedAt: updated.Add(2 * time.Hour)}
+	later.User.Login = "someone"
+	client.issueComments["deseretdigital/example#8"] = []githubapi.IssueComment{later}
+	notifier.notifications = nil
+	if err := mon.pollIssues(ctx); err != nil {
+		t.Fatalf("pollIssues error = %v", err)
+	}
+	if len(notifier.notifications) != 1 || notifier.notifications[0].message != "someone: Thanks!" {
+		t.Fatalf("notifications = %+v", notifier.notifications)
+	}
+
+	// Issues that drop out of the search are forgotten.
+	mon.cfg.MaxResults = 10
+	client.issues = client.issues[1:]
+	if err := mon.pollIssues(ctx); err != nil {
+		t.Fatalf("pollIssues error = %v", err)
+	}
+	if _, ok := state.Issues["deseretdigital/example#7"]; ok {
+		t.Error("issue no longer matching the search was kept")
+	}
+	if _, ok := state.Issues["deseretdigital/example#8"]; !ok {
+		t.Error("issue still matching the search was dropped")
+	}
+}
@@ -0,0 +1,134 @@
+package monitor
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sort"
+	"strings"
+
+	githubapi "gh-review-notifier/internal/github"
+	"gh-review-notifier/internal/notify"
+)
+
+// LabelRule triggers a notification when Label is added to or removed from a
+// tracked pull request.
+type LabelRule struct {
+	Label   string
+	Added   bool
+	Removed bool
+}
+
+// ParseLabelRules parses a comma-separated rule list. A bare label matches
+// both additions and removals, "+label" only additions and "-label" only
+// removals.
+func ParseLabelRules(raw string) []LabelRule {
+	var rules []LabelRule
+	for _, item := range strings.Split(raw, ",") {
+		item = strings.TrimSpace(item)
+		rule := LabelRule{Added: true, Removed: true}
+		switch {
+		case strings.HasPrefix(item, "+"):
+			rule.Removed = false
+			item = item[1:]
+		case strings.HasPrefix(item, "-"):
+			rule.Added = false
+			item = item[1:]
+		}
+		if item = strings.TrimSpace(item); item == "" {
+			continue
+		}
+		rule.Label = item
+		rules = append(rules, rule)
+	}
+	return rules
+}
+
+// checkLabelRules compares the PR's labels with the last recorded set and
+// notifies for changes matching a rule. PRs seen for the first time are only
+// recorded.
+func (m *Monitor) checkLabelRules(ctx context.Context, repo string, item githubapi.PullRequestSummary, relation string) {
+	if len(m.cfg.LabelRules) == 0 {
+		return
+	}
+	key := prKey(repo, item.Number)
+	current := labelNames(item.Labels)
+
+	m.mu.Lock()
+	previous, known := m.state.Labels[key]
+	m.state.Labels[key] = current
+	m.mu.Unlock()
+
+	if !known || !m.state.Initialized {
+		return
+	}
+
+	added, removed := diffLabels(previous, current)
+	for _, change := range []struct {
+		verb   string
+		labels []string
+	}{
+		{verb: "added", labels: added},
+		{verb: "removed", labels: removed},
+	} {
+		for _, label := range change.labels {
+			if !m.labelRuleMatches(label, change.verb == "added") {
+				continue
+			}
+			event := notify.Event{
+				Kind:       notify.KindLabel,
+				Repo:       repo,
+				Number:     item.Number,
+				Title:      item.Title,
+				Summary:    fmt.Sprintf("Label %q %s · %s", label, change.verb, relation),
+				URL:        item.URL,
+				OccurredAt: item.UpdatedAt,
+				DedupeKey:  dedupeKey(notify.KindLabel, key, label, change.verb, item.UpdatedAt.Unix()),
+			}
+			if err := m.notifier.Notify(ctx, event); err != nil {
+				m.logger.Warn("notification failed", slog.String("repo", repo), slog.Int("number", item.Number), slog.String("error", err.Error()))
+			}
+		}
+	}
+}
+
+func (m *Monitor) labelRuleMatches(label string, added bool) bool {
+	for _, rule := range m.cfg.LabelRules {
+		if !strings.EqualFold(rule.Label, label) {
+			continue
+		}
+		if (added && rule.Added) || (!added && rule.Removed) {
+			return true
+		}
+	}
+	return false
+}
+
+func labelNames(labels []githubapi.Label) []string {
+	names := make([]string, 0, len(labels))
+	for _, label := range labels {
+		names = append(names, label.Name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func diffLabels(previous, current []string) (added, removed []string) {
+	before := make(map[string]bool, len(previous))
+	for _, name := range previous {
+		before[name] = true
+	}
+	after := make(map[string]bool, len(current))
+	for _, name := range current {
+		after[name] = true
+		if !before[name] {
+			added = append(added, name)
+		}
+	}
+	for _, name := range previous {
+		if !after[name] {
+			removed = append(removed, name)
+		}
+	}
+	return added, removed
+}
@@ -0,0 +1,93 @@
+package monitor
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	"gh-review-notifier/internal/cache"
+	githubapi "gh-review-notifier/internal/github"
+)
+
+func TestParseLabelRules(t *testing.T) {
+	got := ParseLabelRules(" urgent, +ready-for-qa ,-blocked,, + ")
+	want := []LabelRule{
+		{Label: "urgent", Added: true, Removed: true},
+		{Label: "ready-for-qa", Added: true},
+		{Label: "blocked", Removed: true},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("ParseLabelRules = %+v, want %+v", got, want)
+	}
+}
+
+func TestDiffLabels(t *testing.T) {
+	added, removed := diffLabels([]string{"blocked", "backend"}, []string{"backend", "urgent"})
+	if !reflect.DeepEqual(added, []string{"urgent"}) || !reflect.DeepEqual(removed, []string{"blocked"}) {
+		t.Fatalf("diffLabels = %v, %v", added, removed)
+	}
+}
+
+func TestPollAssignedLabelRules(t *testing.T) {
+	ctx := context.Background()
+	state := cache.NewState()
+	state.Initialized = true
+
+	updated := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	client := &fakeGitHubClient{
+		assigned: []githubapi.PullRequestSummary{
+			{
+				Number:    49,
+				Title:     "Hotfix",
+				URL:       "https://github.com/deseretdigital/example/pull/49",
+				UpdatedAt: updated,
+				Labels:    []githubapi.Label{{Name: "blocked"}},
+			},
+		},
+		prDetails: map[string]*githubapi.PullRequest{
+			"deseretdigital/example#49": {Number: 49, Title: "Hotfix"},
+		},
+	}
+
+	notifier := &fakeNotifier{}
+	mon := NewMonitor(Config{MaxResults: 10, LabelRules: ParseLabelRules("urgent,-blocked,+wip")}, client, notifier, state, nil)
+	if err := mon.pollAssigned(ctx); err != nil {
+		t.Fatalf("pollAssigned error = %v", err)
+	}
+	notifier.notifications = nil
+
+	// The label change also bumps updatedAt; the rule alerts replace the
+	// generic "Labels changed" update.
+	client.assigned[0].Labels = []githubapi.Label{{Name: "urgent"}, {Name: "docs"}}
+	client.assigned[0].UpdatedAt = updated.Add(time.Hour)
+	client.timelines = map[string][]githubapi.TimelineEvent{
+		"deseretdigital/example#49": {{Event: "labeled", CreatedAt: updated.Add(30 * time.Minute)}},
+	}
+	if err := mon.pollAssigned(ctx); err != nil {
+		t.Fatalf("pollAssigned error = %v", err)
+	}
+
+	want := []string{
+		`Label "urgent" added · awaiting your review`,
+		`Label "blocked" removed · awaiting your review`,
+	}
+	var got []string
+	for _, n := range notifier.notifications {
+		got = append(got, n.message)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("notifications = %q, want %q", got, want)
+	}
+	if labels := state.Labels["deseretdigital/example#49"]; !reflect.DeepEqual(labels, []string{"docs", "urgent"}) {
+		t.Errorf("recorded labels = %v", labels)
+	}
+
+	client.assigned = nil
+	if err := mon.pollAssigned(ctx); err != nil {
+		t.Fatalf("pollAssigned error = %v", err)
+	}
+	if _, ok := state.Labels["deseretdigital/example#49"]; ok {
+		t.Error("labels of a PR no longer awaiting review were kept")
+	}
+}
@@ -0,0 +1,55 @@
+package monitor
+
+import (
+	githubapi "gh-review-notifier/internal/github"
+)
+
+// evaluateMergeReadiness reports whether a pull request is approved, every
+// status check has passed and GitHub considers it mergeable. It also returns
+// the number of passing checks. Approval follows branch protection's review
+// decision; repositories without required reviews report none, and there the
+// PR counts as approved once a reviewer approves and nobody requests changes.
+func evaluateMergeReadiness(status *githubapi.MergeStatus, reviewers map[string]string) (bool, int) {
+	if status == nil || status.Mergeable != "MERGEABLE" {
+		return false, 0
+	}
+	switch status.ReviewDecision {
+	case "APPROVED":
+	case "":
+		if !hasApproval(reviewers) || blockingReviews(reviewers) > 0 {
+			return false, 0
+		}
+	default:
+		return false, 0
+	}
+	for _, check := range status.StatusCheckRollup {
+		if !checkPassed(check) {
+			return false, 0
+		}
+	}
+	return true, len(status.StatusCheckRollup)
+}
+
+func hasApproval(reviewers map[string]string) bool {
+	for _, state := range reviewers {
+		if state == reviewApproved {
+			return true
+		}
+	}
+	return false
+}
+
+func checkPassed(check githubapi.CheckStatus) bool {
+	if check.TypeName == "StatusContext" || (check.State != "" && check.Status == "") {
+		return check.State == "SUCCESS"
+	}
+	if check.Status != "COMPLETED" {
+		return false
+	}
+	switch check.Conclusion {
+	case "SUCCESS", "NEUTRAL", "SKIPPED":
+		return true
+	default:
+		return false
+	}
+}
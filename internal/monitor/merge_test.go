@@ -0,0 +1,82 @@
+package monitor
+
+import (
+	"testing"
+
+	githubapi "gh-review-notifier/internal/github"
+)
+
+func TestEvaluateMergeReadiness(t *testing.T) {
+	passing := []githubapi.CheckStatus{
+		{TypeName: "CheckRun", Name: "test", Status: "COMPLETED", Conclusion: "SUCCESS"},
+		{TypeName: "CheckRun", Name: "lint", Status: "COMPLETED", Conclusion: "SKIPPED"},
+		{TypeName: "StatusContext", Context: "ci/legacy", State: "SUCCESS"},
+	}
+
+	approved := map[string]string{"lead": "APPROVED"}
+	tests := []struct {
+		name      string
+		status    *githubapi.MergeStatus
+		reviewers map[string]string
+		ready     bool
+		checks    int
+	}{
+		{
+			name:   "all satisfied",
+			status: &githubapi.MergeStatus{ReviewDecision: "APPROVED", Mergeable: "MERGEABLE", StatusCheckRollup: passing},
+			ready:  true,
+			checks: 3,
+		},
+		{
+			name:   "no checks",
+			status: &githubapi.MergeStatus{ReviewDecision: "APPROVED", Mergeable: "MERGEABLE"},
+			ready:  true,
+		},
+		{
+			name:   "review required",
+			status: &githubapi.MergeStatus{ReviewDecision: "REVIEW_REQUIRED", Mergeable: "MERGEABLE", StatusCheckRollup: passing},
+		},
+		{
+			name:   "conflicting",
+			status: &githubapi.MergeStatus{ReviewDecision: "APPROVED", Mergeable: "CONFLICTING", StatusCheckRollup: passing},
+		},
+		{
+			name: "check pending",
+			status: &githubapi.MergeStatus{ReviewDecision: "APPROVED", Mergeable: "MERGEABLE", StatusCheckRollup: []githubapi.CheckStatus{
+				{TypeName: "CheckRun", Name: "test", Status: "IN_PROGRESS"},
+			}},
+		},
+		{
+			name: "status failed",
+			status: &githubapi.MergeStatus{ReviewDecision: "APPROVED", Mergeable: "MERGEABLE", StatusCheckRollup: []githubapi.CheckStatus{
+				{TypeName: "StatusContext", Context: "ci/legacy", State: "FAILURE"},
+			}},
+		},
+		{
+			name:      "no required reviews, approved",
+			status:    &githubapi.MergeStatus{Mergeable: "MERGEABLE", StatusCheckRollup: passing},
+			reviewers: approved,
+			ready:     true,
+			checks:    3,
+		},
+		{
+			name:   "no required reviews, unreviewed",
+			status: &githubapi.MergeStatus{Mergeable: "MERGEABLE", StatusCheckRollup: passing},
+		},
+		{
+			name:      "no required reviews, changes requested",
+			status:    &githubapi.MergeStatus{Mergeable: "MERGEABLE"},
+			reviewers: map[string]string{"lead": "APPROVED", "qa": "CHANGES_REQUESTED"},
+		},
+		{name: "nil status"},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			ready, checks := evaluateMergeReadiness(tc.status, tc.reviewers)
+			if ready != tc.ready || checks != tc.checks {
+				t.Fatalf("evaluateMergeReadiness = (%v, %d), want (%v, %d)", ready, checks, tc.ready, tc.checks)
+			}
+		})
+	}
+}
@@ -0,0 +1,143 @@
+package monitor
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	githubapi "gh-review-notifier/internal/github"
+	"gh-review-notifier/internal/notify"
+)
+
+const mergeQueuePoller = "merge-queue"
+
+// pollMergeQueue checks auto-merge and merge queue state for the open
+// authored PRs in open, plus previously queued or auto-merging PRs that have
+// since dropped out of the open list so their merge can be reported.
+func (m *Monitor) pollMergeQueue(ctx context.Context, open map[string]bool) {
+	report := !m.seeding(mergeQueuePoller)
+
+	keys := make([]string, 0, len(open))
+	for key := range open {
+		keys = append(keys, key)
+	}
+	m.mu.Lock()
+	for key, record := range m.state.AuthoredPRs {
+		if !open[key] && (record.AutoMerge || record.InMergeQueue) {
+			keys = append(keys, key)
+		}
+	}
+	m.mu.Unlock()
+
+	for _, key := range keys {
+		repo, number, ok := splitPRKey(key)
+		if !ok {
+			continue
+		}
+		m.checkMergeQueue(ctx, repo, number, report)
+	}
+	m.markSeeded(mergeQueuePoller)
+}
+
+func (m *Monitor) checkMergeQueue(ctx context.Context, repo string, number int, report bool) {
+	status, err := m.client.MergeQueueStatus(ctx, repo, number)
+	if err != nil {
+		m.logger.Warn("merge queue status fetch failed", slog.String("repo", repo), slog.Int("number", number), slog.String("error", err.Error()))
+		return
+	}
+	key := prKey(repo, number)
+
+	m.mu.Lock()
+	record := m.state.AuthoredPRs[key]
+	changes := mergeQueueChanges(record.AutoMerge, record.InMergeQueue, status)
+	open := status.State == "OPEN"
+	record.AutoMerge = open && status.AutoMerge != nil
+	record.InMergeQueue = open && status.QueueEntry != nil
+	m.state.AuthoredPRs[key] = record
+	m.mu.Unlock()
+
+	if !report {
+		return
+	}
+	for _, change := range changes {
+		at := change.at
+		if at.IsZero() {
+			at = time.Now()
+		}
+		event := notify.Event{
+			Kind:       notify.KindMergeQueue,
+			Repo:       repo,
+			Number:     number,
+			Title:      status.Title,
+			Summary:    change.summary,
+			URL:        status.URL,
+			OccurredAt: at,
+			DedupeKey:  dedupeKey(notify.KindMergeQueue, key, change.action, at.Unix()),
+		}
+		if err := m.notifier.Notify(ctx, event); err != nil {
+			m.logger.Warn("notification failed", slog.String("repo", repo), slog.Int("number", number), slog.String("error", err.Error()))
+		}
+	}
+}
+
+// mergeQueueChange is one auto-merge or merge queue transition. at is when
+// GitHub recorded it, or zero if that is unknown.
+type mergeQueueChange struct {
+	action  string
+	summary string
+	at      time.Time
+}
+
+// mergeQueueChanges describes how a PR's auto-merge and merge queue state
+// changed since it was last seen.
+func mergeQueueChanges(wasAutoMerge, wasQueued bool, status *githubapi.MergeQueueStatus) []mergeQueueChange {
+	switch status.State {
+	case "MERGED":
+		switch {
+		case wasQueued:
+			return []mergeQueueChange{{action: "merged", summary: "Merged via merge queue", at: status.MergedAt}}
+		case wasAutoMerge:
+			return []mergeQueueChange{{action: "merged", summary: "Merged by auto-merge", at: status.MergedAt}}
+		}
+		return nil
+	case "OPEN":
+	default:
+		return nil
+	}
+
+	var changes []mergeQueueChange
+	autoMerge, queued := status.AutoMerge != nil, status.QueueEntry != nil
+	switch {
+	case autoMerge && !wasAutoMerge:
+		message := "Auto-merge enabled"
+		if by := status.AutoMerge.EnabledBy.Login; by != "" {
+			message += " by " + by
+		}
+		if method := status.AutoMerge.MergeMethod; method != "" {
+			message += fmt.Sprintf(" (%s)", strings.ToLower(method))
+		}
+		changes = append(changes, mergeQueueChange{action: "auto-merge-enabled", summary: message, at: status.AutoMerge.EnabledAt})
+	case !autoMerge && wasAutoMerge && !queued:
+		changes = append(changes, mergeQueueChange{action: "auto-merge-disabled", summary: "Auto-merge disabled", at: status.AutoMergeDisabledAt})
+	}
+	switch {
+	case queued && !wasQueued:
+		message := "Added to merge queue"
+		if pos := status.QueueEntry.Position; pos > 0 {
+			message += fmt.Sprintf(" · position %d", pos)
+		}
+		changes = append(changes, mergeQueueChange{action: "queued", summary: message, at: status.QueueEntry.EnqueuedAt})
+	case !queued && wasQueued:
+		change := mergeQueueChange{action: "removed", summary: "Removed from merge queue"}
+		if removal := status.LastRemoval; removal != nil {
+			if removal.Reason != "" {
+				change.summary += ": " + removal.Reason
+			}
+			change.at = removal.CreatedAt
+		}
+		changes = append(changes, change)
+	}
+	return changes
+}
@@ -0,0 +1,145 @@
+package monitor
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	"gh-review-notifier/internal/cache"
+	githubapi "gh-review-notifier/internal/github"
+	"gh-review-notifier/internal/notify"
+)
+
+func TestMergeQueueChanges(t *testing.T) {
+	autoMerge := &githubapi.AutoMergeRequest{MergeMethod: "SQUASH", EnabledBy: githubapi.User{Login: "trixtur"}}
+	entry := &githubapi.MergeQueueEntry{Position: 2}
+
+	tests := []struct {
+		name       string
+		wasAuto    bool
+		wasQueued  bool
+		status     *githubapi.MergeQueueStatus
+		wantChange []string
+	}{
+		{
+			name:       "auto-merge enabled",
+			status:     &githubapi.MergeQueueStatus{State: "OPEN", AutoMerge: autoMerge},
+			wantChange: []string{"Auto-merge enabled by trixtur (squash)"},
+		},
+		{
+			name:       "auto-merge disabled",
+			wasAuto:    true,
+			status:     &githubapi.MergeQueueStatus{State: "OPEN"},
+			wantChange: []string{"Auto-merge disabled"},
+		},
+		{
+			name:       "queued",
+			wasAuto:    true,
+			status:     &githubapi.MergeQueueStatus{State: "OPEN", AutoMerge: autoMerge, QueueEntry: entry},
+			wantChange: []string{"Added to merge queue · position 2"},
+		},
+		{
+			name:      "removed from queue",
+			wasQueued: true,
+			status: &githubapi.MergeQueueStatus{
+				State:       "OPEN",
+				LastRemoval: &githubapi.MergeQueueRemoval{Reason: "failed checks"},
+			},
+			wantChange: []string{"Removed from merge queue: failed checks"},
+		},
+		{
+			name:       "merged via queue",
+			wasAuto:    true,
+			wasQueued:  true,
+			status:     &githubapi.MergeQueueStatus{State: "MERGED"},
+			wantChange: []string{"Merged via merge queue"},
+		},
+		{
+			name:       "merged by auto-merge",
+			wasAuto:    true,
+			status:     &githubapi.MergeQueueStatus{State: "MERGED"},
+			wantChange: []string{"Merged by auto-merge"},
+		},
+		{
+			name:    "closed",
+			wasAuto: true,
+			status:  &githubapi.MergeQueueStatus{State: "CLOSED"},
+		},
+		{
+			name:    "unchanged",
+			wasAuto: true,
+			status:  &githubapi.MergeQueueStatus{State: "OPEN", AutoMerge: autoMerge},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			var got []string
+			for _, change := range mergeQueueChanges(tc.wasAuto, tc.wasQueued, tc.status) {
+				got = append(got, change.summary)
+			}
+			if !reflect.DeepEqual(got, tc.wantChange) {
+				t.Fatalf("mergeQueueChanges = %q, want %q", got, tc.wantChange)
+			}
+		})
+	}
+}
+
+func TestPollAuthoredReportsMergeViaQueue(t *testing.T) {
+	ctx := context.Background()
+	state := cache.NewState()
+	state.Initialized = true
+	state.Pollers[mergeQueuePoller] = true
+
+	key := "deseretdigital/example#300"
+	pr := githubapi.PullRequestSummary{
+		Number:    300,
+		Title:     "Queue me",
+		URL:       "https://github.com/deseretdigital/example/pull/300",
+		UpdatedAt: time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC),
+	}
+	enqueuedAt := time.Date(2024, 1, 1, 12, 5, 0, 0, time.UTC)
+	mergedAt := time.Date(2024, 1, 1, 12, 20, 0, 0, time.UTC)
+	client := &fakeGitHubClient{
+		authored: []githubapi.PullRequestSummary{pr},
+		mergeQueue: map[string]*githubapi.MergeQueueStatus{
+			key: {Title: pr.Title, URL: pr.URL, State: "OPEN", QueueEntry: &githubapi.MergeQueueEntry{Position: 1, EnqueuedAt: enqueuedAt}},
+		},
+	}
+
+	notifier := &fakeNotifier{}
+	mon := NewMonitor(Config{Author: "trixtur", TrackMergeQueue: true}, client, notifier, state, nil)
+	if err := mon.pollAuthored(ctx); err != nil {
+		t.Fatalf("pollAuthored error = %v", err)
+	}
+
+	// Once merged, the PR drops out of the open authored list.
+	client.authored = nil
+	client.mergeQueue[key] = &githubapi.MergeQueueStatus{Title: pr.Title, URL: pr.URL, State: "MERGED", MergedAt: mergedAt}
+	for i := 0; i < 2; i++ {
+		if err := mon.pollAuthored(ctx); err != nil {
+			t.Fatalf("pollAuthored error = %v", err)
+		}
+	}
+
+	var got []string
+	for _, n := range notifier.notifications {
+		got = append(got, n.message)
+	}
+	want := []string{"Added to merge queue · position 1", "Merged via merge queue"}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("notifications = %q, want %q", got, want)
+	}
+	for i, at := range []time.Time{enqueuedAt, mergedAt} {
+		if got := notifier.events[i].OccurredAt; !got.Equal(at) {
+			t.Errorf("event %d occurred at %v, want %v", i, got, at)
+		}
+	}
+	if got, want := notifier.events[1].DedupeKey, dedupeKey(notify.KindMergeQueue, key, "merged", mergedAt.Unix()); got != want {
+		t.Errorf("merged dedupe key = %q, want %q", got, want)
+	}
+	if record := state.AuthoredPRs[key]; record.InMergeQueue || record.AutoMerge {
+		t.Errorf("merge queue state not cleared: %+v", record)
+	}
+}
@@ -4,6 +4,8 @@ import (
 	"context"
 	"fmt"
 	"log/slog"
+	"os"
+	"strconv"
 	"strings"
 	"sync"
 	"time"
@@ -19,6 +21,57 @@ type Config struct {
 	Author        string
 	CacheFile     string
 	MaxResults    int
+
+	// NotifyQuietUpdates sends a generic "Updated" alert for assigned PRs whose
+	// timeline shows no commits, comments, review requests, title or label
+	// changes (for example, description edits). Off by default.
+	NotifyQuietUpdates bool
+	// DraftQuery, when set, is searched alongside AssignedQuery to track draft
+	// PRs requesting your review so their transition to ready can be reported.
+	DraftQuery string
+	// NotifyDrafts also alerts on review requests and activity while a PR is
+	// still a draft. Ready-for-review transitions are always reported.
+	NotifyDrafts bool
+	// TeamQuery, when set, is searched alongside AssignedQuery to pick up
+	// review requests made to teams (for example team-review-requested:org/team).
+	TeamQuery string
+	// MutedTeams lists teams ("org/slug") whose review requests never notify
+	// unless you were also requested personally.
+	MutedTeams []string
+	// NotifyEdits sends an "(edited)" alert when the body of an already-seen
+	// comment or review on an authored PR changes.
+	NotifyEdits bool
+	// IssueQuery, when set, enables issue monitoring: assignments to you and
+	// new comments on matching issues you opened or are assigned to.
+	IssueQuery string
+	// LabelRules lists label additions/removals on assigned and authored PRs
+	// that should notify.
+	LabelRules []LabelRule
+	// DeploymentRepos lists repositories ("owner/name") whose workflow runs
+	// are checked for deployments waiting on your approval.
+	DeploymentRepos []string
+	// WorkflowRepos lists repositories whose default-branch workflow runs are
+	// watched for new failures and recoveries.
+	WorkflowRepos []string
+	// SubscriptionsFile is the list of pull requests watched by URL; their
+	// comments, reviews and merge readiness are tracked like authored PRs.
+	SubscriptionsFile string
+	// WatchedRepos lists repositories where every newly opened PR notifies.
+	WatchedRepos []string
+	// NotifyWatchedReady also alerts when a draft PR in a watched repository
+	// is marked ready for review.
+	NotifyWatchedReady bool
+	// TrackMergeQueue follows auto-merge and merge queue state on authored
+	// PRs, reporting enable/disable, queue removals and merges via the queue.
+	TrackMergeQueue bool
+	// TrackThreads follows review thread resolution: an alert when all the
+	// threads you started on a PR matching ReviewedQuery are resolved, and
+	// per-thread alerts when a resolved thread on your own PR is reopened.
+	TrackThreads  bool
+	ReviewedQuery string
+	// ClosedGracePeriod keeps authored PRs tracked for comments and reviews
+	// for this long after they are merged or closed. Zero disables it.
+	ClosedGracePeriod time.Duration
 }
 
 type GitHubClient interface {
@@ -27,6 +80,19 @@ type GitHubClient interface {
 	PullRequestDetails(ctx context.Context, repo string, number int) (*githubapi.PullRequest, error)
 	IssueCommentsSince(ctx context.Context, repo string, number int, since time.Time) ([]githubapi.IssueComment, error)
 	Reviews(ctx context.Context, repo string, number int) ([]githubapi.Review, error)
+	PullRequestTimeline(ctx context.Context, repo string, number int) ([]githubapi.TimelineEvent, error)
+	PullRequestMergeStatus(ctx context.Context, repo string, number int) (*githubapi.MergeStatus, error)
+	RequestedReviewers(ctx context.Context, repo string, number int) (*githubapi.RequestedReviewers, error)
+	SearchIssues(ctx context.Context, query string, limit int) ([]githubapi.IssueSummary, error)
+	WaitingWorkflowRuns(ctx context.Context, repo string) ([]githubapi.WorkflowRun, error)
+	PendingDeployments(ctx context.Context, repo string, runID int64) ([]githubapi.PendingDeployment, error)
+	DefaultBranch(ctx context.Context, repo string) (string, error)
+	CompletedBranchWorkflowRuns(ctx context.Context, repo, branch string) ([]githubapi.WorkflowRun, error)
+	ListRepoPullRequests(ctx context.Context, repo string, limit int) ([]githubapi.PullRequestSummary, error)
+	MergeQueueStatus(ctx context.Context, repo string, number int) (*githubapi.MergeQueueStatus, error)
+	ReviewThreads(ctx context.Context, repo string, number int) ([]githubapi.ReviewThread, error)
+	ListClosedPullRequests(ctx context.Context, author string, since time.Time, limit int) ([]githubapi.PullRequestSummary, error)
+	UserTeams(ctx context.Context) ([]string, error)
 }
 
 type Monitor struct {
@@ -36,6 +102,14 @@ type Monitor struct {
 	state    *cache.State
 	logger   *slog.Logger
 	mu       sync.Mutex
+
+	// defaultBranches caches each watched repository's default branch for
+	// the lifetime of the process.
+	defaultBranches map[string]string
+	// teams holds the user's team memberships ("org/slug", lower case),
+	// loaded once per run. It stays nil if they could not be loaded.
+	teams       map[string]bool
+	teamsLoaded bool
 }
 
 const defaultMaxResults = 30
@@ -44,12 +118,17 @@ func NewMonitor(cfg Config, client GitHubClient, notifier notify.Notifier, state
 	if cfg.MaxResults == 0 {
 		cfg.MaxResults = defaultMaxResults
 	}
+	if logger == nil {
+		logger = slog.New(slog.NewTextHandler(os.Stdout, nil))
+	}
 	return &Monitor{
 		cfg:      cfg,
 		client:   client,
 		notifier: notifier,
 		state:    state,
 		logger:   logger,
+
+		defaultBranches: make(map[string]string),
 	}
 }
 
@@ -81,6 +160,36 @@ func (m *Monitor) poll(ctx context.Context) error {
 	if err := m.pollAuthored(ctx); err != nil {
 		return err
 	}
+	if m.cfg.TrackThreads && m.cfg.ReviewedQuery != "" {
+		if err := m.pollReviewedThreads(ctx); err != nil {
+			return err
+		}
+	}
+	if m.cfg.SubscriptionsFile != "" {
+		if err := m.pollSubscriptions(ctx); err != nil {
+			return err
+		}
+	}
+	if m.cfg.IssueQuery != "" {
+		if err := m.pollIssues(ctx); err != nil {
+			return err
+		}
+	}
+	if len(m.cfg.WatchedRepos) > 0 {
+		if err := m.pollWatchedRepos(ctx); err != nil {
+			return err
+		}
+	}
+	if len(m.cfg.DeploymentRepos) > 0 {
+		if err := m.pollDeployments(ctx); err != nil {
+			return err
+		}
+	}
+	if len(m.cfg.WorkflowRepos) > 0 {
+		if err := m.pollWorkflows(ctx); err != nil {
+			return err
+		}
+	}
 	if err := cache.Save(m.cfg.CacheFile, m.state); err != nil {
 		return fmt.Errorf("save cache: %w", err)
 	}
@@ -92,6 +201,22 @@ func (m *Monitor) pollAssigned(ctx context.Context) error {
 	if err != nil {
 		return fmt.Errorf("search assigned PRs: %w", err)
 	}
+	if m.cfg.DraftQuery != "" {
+		drafts, err := m.client.SearchAssignedPullRequests(ctx, m.cfg.DraftQuery, m.cfg.MaxResults)
+		if err != nil {
+			return fmt.Errorf("search draft PRs: %w", err)
+		}
+		results = append(results, drafts...)
+	}
+	if m.cfg.TeamQuery != "" {
+		team, err := m.client.SearchAssignedPullRequests(ctx, m.cfg.TeamQuery, m.cfg.MaxResults)
+		if err != nil {
+			return fmt.Errorf("search team PRs: %w", err)
+		}
+		results = append(results, team...)
+	}
+
+	seen := make(map[string]bool, len(results))
 	for _, item := range results {
 		repo, err := githubapi.RepoFromURL(item.URL)
 		if err != nil {
@@ -99,12 +224,26 @@ func (m *Monitor) pollAssigned(ctx context.Context) error {
 			continue
 		}
 		key := prKey(repo, item.Number)
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		m.checkLabelRules(ctx, repo, item, "awaiting your review")
 
 		m.mu.Lock()
 		last := m.state.AssignedPRs[key]
+		lastHead := m.state.AssignedHeads[key]
+		wasDraft := m.state.DraftPRs[key]
+		if item.IsDraft {
+			m.state.DraftPRs[key] = true
+		} else {
+			delete(m.state.DraftPRs, key)
+		}
 		m.mu.Unlock()
 
-		if !m.state.Initialized || !item.UpdatedAt.After(last) {
+		readyForReview := wasDraft && !item.IsDraft
+		quietDraft := item.IsDraft && !m.cfg.NotifyDrafts
+		if !m.state.Initialized || quietDraft || (!readyForReview && !item.UpdatedAt.After(last)) {
 			if item.UpdatedAt.After(last) || last.IsZero() {
 				m.mu.Lock()
 				m.state.AssignedPRs[key] = item.UpdatedAt
@@ -113,29 +252,128 @@ func (m *Monitor) pollAssigned(ctx context.Context) error {
 			continue
 		}
 
+		var reason string
+		kind := notify.KindReviewRequested
+		switch {
+		case readyForReview:
+			reason = "Ready for review"
+		case last.IsZero() && item.IsDraft:
+			reason = "Review requested on draft"
+		case last.IsZero():
+			reason = "Review requested"
+		default:
+			var ok bool
+			kind = notify.KindPullRequestUpdated
+			var head string
+			reason, head, ok = m.classifyAssignedUpdate(ctx, repo, item.Number, last, lastHead)
+			if head != "" {
+				m.mu.Lock()
+				m.state.AssignedHeads[key] = head
+				m.mu.Unlock()
+			}
+			if !ok {
+				m.logger.Debug("suppressing quiet PR update", slog.String("repo", repo), slog.Int("number", item.Number))
+				m.mu.Lock()
+				m.state.AssignedPRs[key] = item.UpdatedAt
+				m.mu.Unlock()
+				continue
+			}
+		}
+
+		source, muted := m.requestSource(ctx, repo, item.Number)
+		if muted {
+			m.logger.Debug("suppressing muted team review request", slog.String("repo", repo), slog.Int("number", item.Number))
+			m.mu.Lock()
+			m.state.AssignedPRs[key] = item.UpdatedAt
+			m.mu.Unlock()
+			continue
+		}
+
 		details, err := m.client.PullRequestDetails(ctx, repo, item.Number)
 		if err != nil {
 			m.logger.Warn("failed to load PR details", slog.String("repo", repo), slog.Int("number", item.Number), slog.String("error", err.Error()))
 			continue
 		}
 
-		message := fmt.Sprintf("#%d · +%d −%d · %d files", details.Number, details.Additions, details.Deletions, details.ChangedFiles)
-		if err := m.notifier.Notify(ctx, details.Title, repo, message, details.URL); err != nil {
+		event := notify.Event{
+			Kind:       kind,
+			Repo:       repo,
+			Number:     item.Number,
+			Title:      details.Title,
+			Context:    source,
+			Summary:    reason,
+			Actor:      details.Author.Login,
+			Diff:       diffStats(details),
+			URL:        details.URL,
+			OccurredAt: item.UpdatedAt,
+			DedupeKey:  dedupeKey(kind, key, item.UpdatedAt.Unix()),
+		}
+		if err := m.notifier.Notify(ctx, event); err != nil {
 			m.logger.Warn("notification failed", slog.String("repo", repo), slog.Int("number", item.Number), slog.String("error", err.Error()))
 		}
 
 		m.mu.Lock()
 		m.state.AssignedPRs[key] = item.UpdatedAt
+		if details.HeadRefOid != "" {
+			m.state.AssignedHeads[key] = details.HeadRefOid
+		}
+		m.mu.Unlock()
+	}
+
+	// Forget head commits, drafts and labels of PRs no longer awaiting
+	// review, such as drafts closed without becoming ready, unless a search
+	// may have been truncated.
+	if len(results) < m.cfg.MaxResults {
+		m.mu.Lock()
+		for key := range m.state.AssignedHeads {
+			if !seen[key] {
+				delete(m.state.AssignedHeads, key)
+			}
+		}
+		for key := range m.state.DraftPRs {
+			if !seen[key] {
+				delete(m.state.DraftPRs, key)
+			}
+		}
+		for key := range m.state.Labels {
+			if _, authored := m.state.AuthoredPRs[key]; !seen[key] && !authored {
+				delete(m.state.Labels, key)
+			}
+		}
 		m.mu.Unlock()
 	}
 	return nil
 }
 
+// classifyAssignedUpdate explains an updatedAt bump on an already-known
+// assigned PR and returns its head commit, if known. It returns false when the
+// update carries no meaningful activity and quiet updates are not enabled.
+func (m *Monitor) classifyAssignedUpdate(ctx context.Context, repo string, number int, since time.Time, lastHead string) (string, string, bool) {
+	events, err := m.client.PullRequestTimeline(ctx, repo, number)
+	if err != nil {
+		m.logger.Warn("pull request timeline fetch failed", slog.String("repo", repo), slog.Int("number", number), slog.String("error", err.Error()))
+		return "Updated", "", true
+	}
+	owner, _, _ := strings.Cut(repo, "/")
+	act := classifyActivity(events, since, lastHead, m.cfg.Author, func(slug string) bool {
+		return m.myTeam(ctx, owner+"/"+slug)
+	})
+	if len(m.cfg.LabelRules) > 0 {
+		// Label rules report the label changes worth hearing about.
+		act.labels = false
+	}
+	if act.empty() {
+		return "Updated", act.head, m.cfg.NotifyQuietUpdates
+	}
+	return act.describe(), act.head, true
+}
+
 func (m *Monitor) pollAuthored(ctx context.Context) error {
 	results, err := m.client.ListAuthoredPullRequests(ctx, m.cfg.Author, m.cfg.MaxResults)
 	if err != nil {
 		return fmt.Errorf("list authored PRs: %w", err)
 	}
+	open := make(map[string]bool, len(results))
 	for _, item := range results {
 		repo, err := githubapi.RepoFromURL(item.URL)
 		if err != nil {
@@ -143,67 +381,230 @@ func (m *Monitor) pollAuthored(ctx context.Context) error {
 			continue
 		}
 		key := prKey(repo, item.Number)
-
 		m.mu.Lock()
-		record := m.state.AuthoredPRs[key]
+		if record, ok := m.state.AuthoredPRs[key]; ok && !record.ClosedAt.IsZero() {
+			// Reopened, so no longer subject to the grace period.
+			record.ClosedAt = time.Time{}
+			m.state.AuthoredPRs[key] = record
+		}
 		m.mu.Unlock()
 
-		maxCommentTime := record.LastIssueComment
-		comments, err := m.client.IssueCommentsSince(ctx, repo, item.Number, record.LastIssueComment)
-		if err != nil {
-			m.logger.Warn("issue comments fetch failed", slog.String("repo", repo), slog.Int("number", item.Number), slog.String("error", err.Error()))
-		} else {
-			for _, cmt := range comments {
-				if cmt.UpdatedAt.After(maxCommentTime) {
-					maxCommentTime = cmt.UpdatedAt
-				}
-				if !m.state.Initialized || !cmt.UpdatedAt.After(record.LastIssueComment) {
-					continue
-				}
-				body := summarizeText(cmt.Body, 220)
-				message := fmt.Sprintf("%s: %s", cmt.User.Login, body)
-				subtitle := fmt.Sprintf("%s · #%d", repo, item.Number)
-				if err := m.notifier.Notify(ctx, item.Title, subtitle, message, cmt.HTMLURL); err != nil {
-					m.logger.Warn("notification failed", slog.String("repo", repo), slog.Int("number", item.Number), slog.String("error", err.Error()))
-				}
+		m.checkLabelRules(ctx, repo, item, "your PR")
+		m.trackPullRequest(ctx, repo, item, false)
+		if m.cfg.TrackThreads {
+			m.checkAuthoredThreads(ctx, repo, item)
+		}
+		open[key] = true
+	}
+	if m.cfg.TrackMergeQueue {
+		m.pollMergeQueue(ctx, open)
+	}
+	if m.cfg.ClosedGracePeriod > 0 {
+		if len(results) >= m.cfg.MaxResults {
+			// The list may be truncated, so absence does not mean closed.
+			open = nil
+		}
+		if err := m.pollRecentlyClosed(ctx, open); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// trackPullRequest reports new or edited comments and reviews, review state
+// transitions and merge readiness for a pull request you follow (authored or
+// watched). With seed set, or before the cache is initialized, activity is
+// recorded without notifying.
+func (m *Monitor) trackPullRequest(ctx context.Context, repo string, item githubapi.PullRequestSummary, seed bool) {
+	key := prKey(repo, item.Number)
+	report := m.state.Initialized && !seed
+
+	m.mu.Lock()
+	record := m.state.AuthoredPRs[key]
+	m.mu.Unlock()
+
+	// Records written before comments and reviews were tracked by ID only
+	// carry timestamps; treat anything at or before them as already seen.
+	legacyComments := record.Comments == nil && !record.LastIssueComment.IsZero()
+	legacyReviews := record.Reviews == nil && !record.LastReview.IsZero()
+	if record.Comments == nil {
+		record.Comments = make(map[int64]string)
+	}
+	if record.Reviews == nil {
+		record.Reviews = make(map[int64]string)
+	}
+
+	maxCommentTime := record.LastIssueComment
+	comments, err := m.client.IssueCommentsSince(ctx, repo, item.Number, record.LastIssueComment)
+	if err != nil {
+		m.logger.Warn("issue comments fetch failed", slog.String("repo", repo), slog.Int("number", item.Number), slog.String("error", err.Error()))
+	} else {
+		for _, cmt := range comments {
+			if cmt.UpdatedAt.After(maxCommentTime) {
+				maxCommentTime = cmt.UpdatedAt
+			}
+			change := trackSeen(record.Comments, cmt.ID, cmt.Body)
+			if legacyComments && change == seenNew && !cmt.UpdatedAt.After(record.LastIssueComment) {
+				continue
+			}
+			if !report || !m.shouldNotifySeen(change) {
+				continue
+			}
+			if err := m.notifier.Notify(ctx, commentEvent(repo, item.Number, item.Title, cmt, change)); err != nil {
+				m.logger.Warn("notification failed", slog.String("repo", repo), slog.Int("number", item.Number), slog.String("error", err.Error()))
 			}
 		}
+	}
 
-		maxReviewTime := record.LastReview
-		reviews, err := m.client.Reviews(ctx, repo, item.Number)
-		if err != nil {
-			m.logger.Warn("pull request reviews fetch failed", slog.String("repo", repo), slog.Int("number", item.Number), slog.String("error", err.Error()))
-		} else {
-			for _, rvw := range reviews {
-				if rvw.SubmittedAt.After(maxReviewTime) {
-					maxReviewTime = rvw.SubmittedAt
-				}
-				if rvw.SubmittedAt.IsZero() || !m.state.Initialized || !rvw.SubmittedAt.After(record.LastReview) {
-					continue
+	maxReviewTime := record.LastReview
+	reviews, err := m.client.Reviews(ctx, repo, item.Number)
+	if err != nil {
+		m.logger.Warn("pull request reviews fetch failed", slog.String("repo", repo), slog.Int("number", item.Number), slog.String("error", err.Error()))
+	} else {
+		effective := effectiveReviews(reviews)
+		states := reviewerStates(effective)
+		var transitions []reviewTransition
+		if record.ReviewerStates != nil {
+			transitions = reviewTransitions(record.ReviewerStates, effective)
+		}
+		// A review that resolves requested changes is reported once, as
+		// a transition, rather than as both a new review and a transition.
+		reportedAsTransition := make(map[int64]bool, len(transitions))
+		for _, tr := range transitions {
+			reportedAsTransition[tr.review.ID] = true
+		}
+
+		for _, rvw := range reviews {
+			if rvw.SubmittedAt.IsZero() {
+				continue
+			}
+			if rvw.SubmittedAt.After(maxReviewTime) {
+				maxReviewTime = rvw.SubmittedAt
+			}
+			change := trackSeen(record.Reviews, rvw.ID, rvw.Body)
+			if legacyReviews && change == seenNew && !rvw.SubmittedAt.After(record.LastReview) {
+				continue
+			}
+			if !report || !m.shouldNotifySeen(change) || (change == seenNew && reportedAsTransition[rvw.ID]) {
+				continue
+			}
+			event := notify.Event{
+				Kind:        notify.KindReview,
+				Repo:        repo,
+				Number:      item.Number,
+				Title:       item.Title,
+				Actor:       rvw.User.Login,
+				Edited:      change == seenEdited,
+				ReviewState: titleCase(rvw.State),
+				Body:        summarizeText(rvw.Body, 180),
+				URL:         rvw.HTMLURL,
+				OccurredAt:  rvw.SubmittedAt,
+				DedupeKey:   dedupeKey(notify.KindReview, key, rvw.ID, bodyHash(rvw.Body)),
+			}
+			if err := m.notifier.Notify(ctx, event); err != nil {
+				m.logger.Warn("notification failed", slog.String("repo", repo), slog.Int("number", item.Number), slog.String("error", err.Error()))
+			}
+		}
+
+		if report {
+			blocking := blockingReviews(states)
+			for _, tr := range transitions {
+				link := tr.review.HTMLURL
+				if link == "" {
+					link = item.URL
 				}
-				state := titleCase(rvw.State)
-				body := summarizeText(rvw.Body, 180)
-				if body == "" {
-					body = state
-				} else {
-					body = fmt.Sprintf("%s — %s", state, body)
+				event := notify.Event{
+					Kind:        notify.KindReviewState,
+					Repo:        repo,
+					Number:      item.Number,
+					Title:       item.Title,
+					Summary:     tr.describe(blocking),
+					Actor:       tr.reviewer,
+					ReviewState: reviewStateLabel(tr.to),
+					URL:         link,
+					OccurredAt:  tr.review.SubmittedAt,
+					DedupeKey:   dedupeKey(notify.KindReviewState, key, tr.reviewer, tr.review.ID, tr.to),
 				}
-				message := fmt.Sprintf("%s: %s", rvw.User.Login, body)
-				subtitle := fmt.Sprintf("%s · #%d", repo, item.Number)
-				if err := m.notifier.Notify(ctx, item.Title, subtitle, message, rvw.HTMLURL); err != nil {
+				if err := m.notifier.Notify(ctx, event); err != nil {
 					m.logger.Warn("notification failed", slog.String("repo", repo), slog.Int("number", item.Number), slog.String("error", err.Error()))
 				}
 			}
 		}
+		record.ReviewerStates = states
+	}
 
-		record.LastIssueComment = maxCommentTime
-		record.LastReview = maxReviewTime
+	record.LastIssueComment = maxCommentTime
+	record.LastReview = maxReviewTime
 
-		m.mu.Lock()
-		m.state.AuthoredPRs[key] = record
-		m.mu.Unlock()
+	// Merge status costs another gh call per PR, so it is only fetched once
+	// someone has approved.
+	if !record.ReadyToMerge && item.ClosedAt.IsZero() && hasApproval(record.ReviewerStates) {
+		record.ReadyToMerge = m.checkReadyToMerge(ctx, repo, item, record.ReviewerStates, report)
 	}
-	return nil
+
+	m.mu.Lock()
+	m.state.AuthoredPRs[key] = record
+	m.mu.Unlock()
+}
+
+// checkReadyToMerge reports whether a followed PR is approved, passing its
+// checks and mergeable, notifying the first time that happens when report is
+// set.
+func (m *Monitor) checkReadyToMerge(ctx context.Context, repo string, item githubapi.PullRequestSummary, reviewers map[string]string, report bool) bool {
+	status, err := m.client.PullRequestMergeStatus(ctx, repo, item.Number)
+	if err != nil {
+		m.logger.Warn("pull request merge status fetch failed", slog.String("repo", repo), slog.Int("number", item.Number), slog.String("error", err.Error()))
+		return false
+	}
+	ready, checks := evaluateMergeReadiness(status, reviewers)
+	if !ready || !report {
+		return ready
+	}
+
+	message := "Ready to merge · approved"
+	if checks > 0 {
+		message += " · " + pluralize(checks, "check passed", "checks passed")
+	}
+	event := notify.Event{
+		Kind:       notify.KindReadyToMerge,
+		Repo:       repo,
+		Number:     item.Number,
+		Title:      item.Title,
+		Summary:    message,
+		URL:        item.URL,
+		OccurredAt: time.Now(),
+		DedupeKey:  dedupeKey(notify.KindReadyToMerge, prKey(repo, item.Number)),
+	}
+	if err := m.notifier.Notify(ctx, event); err != nil {
+		m.logger.Warn("notification failed", slog.String("repo", repo), slog.Int("number", item.Number), slog.String("error", err.Error()))
+	}
+	return true
+}
+
+func (m *Monitor) shouldNotifySeen(change seenChange) bool {
+	switch change {
+	case seenNew:
+		return true
+	case seenEdited:
+		return m.cfg.NotifyEdits
+	default:
+		return false
+	}
+}
+
+// seeding reports whether the named optional poller should record state
+// without notifying, either because this is the first poll overall or because
+// the poller was enabled after the cache was initialized.
+func (m *Monitor) seeding(poller string) bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return !m.state.Initialized || !m.state.Pollers[poller]
+}
+
+func (m *Monitor) markSeeded(poller string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.state.Pollers[poller] = true
 }
 
 func (m *Monitor) markInitialized() {
@@ -215,10 +616,57 @@ func (m *Monitor) markInitialized() {
 	m.state.Initialized = true
 }
 
+// diffStats returns a PR's size.
+func diffStats(details *githubapi.PullRequest) *notify.DiffStats {
+	return &notify.DiffStats{
+		Additions:    details.Additions,
+		Deletions:    details.Deletions,
+		ChangedFiles: details.ChangedFiles,
+	}
+}
+
+// commentEvent describes a new or edited comment on a PR or issue.
+func commentEvent(repo string, number int, title string, cmt githubapi.IssueComment, change seenChange) notify.Event {
+	return notify.Event{
+		Kind:       notify.KindComment,
+		Repo:       repo,
+		Number:     number,
+		Title:      title,
+		Actor:      cmt.User.Login,
+		Edited:     change == seenEdited,
+		Body:       summarizeText(cmt.Body, 220),
+		URL:        cmt.HTMLURL,
+		OccurredAt: cmt.UpdatedAt,
+		DedupeKey:  dedupeKey(notify.KindComment, prKey(repo, number), cmt.ID, bodyHash(cmt.Body)),
+	}
+}
+
+// dedupeKey joins the parts identifying one occurrence of an event.
+func dedupeKey(kind notify.Kind, parts ...any) string {
+	key := string(kind)
+	for _, part := range parts {
+		key += fmt.Sprintf(":%v", part)
+	}
+	return key
+}
+
 func prKey(repo string, number int) string {
 	return fmt.Sprintf("%s#%d", repo, number)
 }
 
+// splitPRKey reverses prKey.
+func splitPRKey(key string) (string, int, bool) {
+	repo, num, ok := strings.Cut(key, "#")
+	if !ok {
+		return "", 0, false
+	}
+	number, err := strconv.Atoi(num)
+	if err != nil {
+		return "", 0, false
+	}
+	return repo, number, true
+}
+
 func summarizeText(body string, limit int) string {
 	if strings.TrimSpace(body) == "" {
 		return ""
@@ -7,19 +7,36 @@ import (
 
 	"gh-review-notifier/internal/cache"
 	githubapi "gh-review-notifier/internal/github"
+	"gh-review-notifier/internal/notify"
 )
 
 type fakeGitHubClient struct {
 	assigned []githubapi.PullRequestSummary
 	authored []githubapi.PullRequestSummary
+	searches map[string][]githubapi.PullRequestSummary
 
 	prDetails map[string]*githubapi.PullRequest
 
 	issueComments map[string][]githubapi.IssueComment
 	reviews       map[string][]githubapi.Review
+	timelines     map[string][]githubapi.TimelineEvent
+	mergeStatuses map[string]*githubapi.MergeStatus
+	reviewers     map[string]*githubapi.RequestedReviewers
+	issues        []githubapi.IssueSummary
+	waitingRuns   map[string][]githubapi.WorkflowRun
+	pendingDeploy map[int64][]githubapi.PendingDeployment
+	branchRuns    map[string][]githubapi.WorkflowRun
+	repoPRs       map[string][]githubapi.PullRequestSummary
+	mergeQueue    map[string]*githubapi.MergeQueueStatus
+	threads       map[string][]githubapi.ReviewThread
+	closed        []githubapi.PullRequestSummary
+	teams         []string
 }
 
 func (f *fakeGitHubClient) SearchAssignedPullRequests(ctx context.Context, query string, limit int) ([]githubapi.PullRequestSummary, error) {
+	if results, ok := f.searches[query]; ok {
+		return results, nil
+	}
 	return f.assigned, nil
 }
 
@@ -45,6 +62,66 @@ func (f *fakeGitHubClient) Reviews(ctx context.Context, repo string, number int)
 	return f.reviews[key], nil
 }
 
+func (f *fakeGitHubClient) PullRequestTimeline(ctx context.Context, repo string, number int) ([]githubapi.TimelineEvent, error) {
+	key := prKey(repo, number)
+	return f.timelines[key], nil
+}
+
+func (f *fakeGitHubClient) PullRequestMergeStatus(ctx context.Context, repo string, number int) (*githubapi.MergeStatus, error) {
+	key := prKey(repo, number)
+	return f.mergeStatuses[key], nil
+}
+
+func (f *fakeGitHubClient) RequestedReviewers(ctx context.Context, repo string, number int) (*githubapi.RequestedReviewers, error) {
+	key := prKey(repo, number)
+	return f.reviewers[key], nil
+}
+
+func (f *fakeGitHubClient) SearchIssues(ctx context.Context, query string, limit int) ([]githubapi.IssueSummary, error) {
+	return f.issues, nil
+}
+
+func (f *fakeGitHubClient) WaitingWorkflowRuns(ctx context.Context, repo string) ([]githubapi.WorkflowRun, error) {
+	return f.waitingRuns[repo], nil
+}
+
+func (f *fakeGitHubClient) PendingDeployments(ctx context.Context, repo string, runID int64) ([]githubapi.PendingDeployment, error) {
+	return f.pendingDeploy[runID], nil
+}
+
+func (f *fakeGitHubClient) DefaultBranch(ctx context.Context, repo string) (string, error) {
+	return "main", nil
+}
+
+func (f *fakeGitHubClient) CompletedBranchWorkflowRuns(ctx context.Context, repo, branch string) ([]githubapi.WorkflowRun, error) {
+	return f.branchRuns[repo], nil
+}
+
+func (f *fakeGitHubClient) ListRepoPullRequests(ctx context.Context, repo string, limit int) ([]githubapi.PullRequestSummary, error) {
+	return f.repoPRs[repo], nil
+}
+
+func (f *fakeGitHubClient) MergeQueueStatus(ctx context.Context, repo string, number int) (*githubapi.MergeQueueStatus, error) {
+	key := prKey(repo, number)
+	if status, ok := f.mergeQueue[key]; ok {
+		return status, nil
+	}
+	return &githubapi.MergeQueueStatus{State: "OPEN"}, nil
+}
+
+func (f *fakeGitHubClient) ReviewThreads(ctx context.Context, repo string, number int) ([]githubapi.ReviewThread, error) {
+	key := prKey(repo, number)
+	return f.threads[key], nil
+}
+
+func (f *fakeGitHubClient) ListClosedPullRequests(ctx context.Context, author string, since time.Time, limit int) ([]githubapi.PullRequestSummary, error) {
+	return f.closed, nil
+}
+
+func (f *fakeGitHubClient) UserTeams(ctx context.Context) ([]string, error) {
+	return f.teams, nil
+}
+
 type notification struct {
 	title    string
 	subtitle string
@@ -54,15 +131,18 @@ type notification struct {
 
 type fakeNotifier struct {
 	notifications []notification
+	events        []notify.Event
 }
 
-func (f *fakeNotifier) Notify(ctx context.Context, title, subtitle, message, link string) error {
+func (f *fakeNotifier) Notify(ctx context.Context, event notify.Event) error {
+	title, subtitle, message := event.Text()
 	f.notifications = append(f.notifications, notification{
 		title:    title,
 		subtitle: subtitle,
 		message:  message,
-		link:     link,
+		link:     event.URL,
 	})
+	f.events = append(f.events, event)
 	return nil
 }
 
@@ -94,6 +174,13 @@ func TestPollAssignedNewActivity(t *testing.T) {
 		},
 		issueComments: make(map[string][]githubapi.IssueComment),
 		reviews:       make(map[string][]githubapi.Review),
+		timelines: map[string][]githubapi.TimelineEvent{
+			"deseretdigital/example#42": {
+				commitEvent(time.Date(2024, 1, 1, 11, 0, 0, 0, time.UTC)),
+				commitEvent(time.Date(2024, 1, 1, 12, 30, 0, 0, time.UTC)),
+				commitEvent(time.Date(2024, 1, 1, 12, 45, 0, 0, time.UTC)),
+			},
+		},
 	}
 
 	notifier := &fakeNotifier{}
@@ -114,7 +201,7 @@ func TestPollAssignedNewActivity(t *testing.T) {
 	if got.subtitle != "deseretdigital/example" {
 		t.Errorf("notification subtitle = %q", got.subtitle)
 	}
-	expectedMsg := "#42 · +120 −30 · 5 files"
+	expectedMsg := "2 new commits · #42 · +120 −30 · 5 files"
 	if got.message != expectedMsg {
 		t.Errorf("notification message = %q, want %q", got.message, expectedMsg)
 	}
@@ -124,6 +211,209 @@ func TestPollAssignedNewActivity(t *testing.T) {
 	}
 }
 
+func TestPollAssignedNewRequest(t *testing.T) {
+	ctx := context.Background()
+	state := cache.NewState()
+	state.Initialized = true
+
+	client := &fakeGitHubClient{
+		assigned: []githubapi.PullRequestSummary{
+			{
+				Number:    44,
+				Title:     "Add tracing",
+				URL:       "https://github.com/deseretdigital/example/pull/44",
+				UpdatedAt: time.Date(2024, 1, 1, 13, 0, 0, 0, time.UTC),
+			},
+		},
+		prDetails: map[string]*githubapi.PullRequest{
+			"deseretdigital/example#44": {Number: 44, Title: "Add tracing", Additions: 10, Deletions: 2, ChangedFiles: 3},
+		},
+	}
+
+	notifier := &fakeNotifier{}
+	mon := NewMonitor(Config{}, client, notifier, state, nil)
+
+	if err := mon.pollAssigned(ctx); err != nil {
+		t.Fatalf("pollAssigned error = %v", err)
+	}
+	if len(notifier.notifications) != 1 {
+		t.Fatalf("expected 1 notification, got %d", len(notifier.notifications))
+	}
+	if got, want := notifier.notifications[0].message, "Review requested · #44 · +10 −2 · 3 files"; got != want {
+		t.Errorf("notification message = %q, want %q", got, want)
+	}
+	event := notifier.events[0]
+	if event.Kind != notify.KindReviewRequested || event.Repo != "deseretdigital/example" || event.Number != 44 {
+		t.Errorf("unexpected event subject: %+v", event)
+	}
+	if event.Diff == nil || event.Diff.Lines() != 12 || event.DedupeKey == "" {
+		t.Errorf("unexpected event details: %+v", event)
+	}
+}
+
+func TestPollAssignedQuietUpdate(t *testing.T) {
+	last := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	updatedTime := time.Date(2024, 1, 1, 13, 0, 0, 0, time.UTC)
+
+	for _, tc := range []struct {
+		name  string
+		quiet bool
+		want  int
+	}{
+		{name: "suppressed by default", quiet: false, want: 0},
+		{name: "notified when enabled", quiet: true, want: 1},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			state := cache.NewState()
+			state.Initialized = true
+			state.AssignedPRs["deseretdigital/example#45"] = last
+
+			client := &fakeGitHubClient{
+				assigned: []githubapi.PullRequestSummary{
+					{
+						Number:    45,
+						Title:     "Tweak docs",
+						URL:       "https://github.com/deseretdigital/example/pull/45",
+						UpdatedAt: updatedTime,
+					},
+				},
+				prDetails: map[string]*githubapi.PullRequest{
+					"deseretdigital/example#45": {Number: 45, Title: "Tweak docs"},
+				},
+				timelines: map[string][]githubapi.TimelineEvent{
+					"deseretdigital/example#45": {
+						{Event: "subscribed", CreatedAt: updatedTime},
+					},
+				},
+			}
+
+			notifier := &fakeNotifier{}
+			mon := NewMonitor(Config{NotifyQuietUpdates: tc.quiet}, client, notifier, state, nil)
+			if err := mon.pollAssigned(context.Background()); err != nil {
+				t.Fatalf("pollAssigned error = %v", err)
+			}
+			if len(notifier.notifications) != tc.want {
+				t.Fatalf("expected %d notifications, got %d", tc.want, len(notifier.notifications))
+			}
+			if !state.AssignedPRs["deseretdigital/example#45"].Equal(updatedTime) {
+				t.Errorf("state not updated with latest timestamp")
+			}
+		})
+	}
+}
+
+func TestPollAssignedDraftBecomesReady(t *testing.T) {
+	ctx := context.Background()
+	state := cache.NewState()
+	state.Initialized = true
+
+	draftTime := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	readyTime := time.Date(2024, 1, 1, 13, 0, 0, 0, time.UTC)
+	pr := githubapi.PullRequestSummary{
+		Number:    46,
+		Title:     "Cache warmup",
+		URL:       "https://github.com/deseretdigital/example/pull/46",
+		UpdatedAt: draftTime,
+		IsDraft:   true,
+	}
+
+	client := &fakeGitHubClient{
+		searches: map[string][]githubapi.PullRequestSummary{
+			"assigned": nil,
+			"drafts":   {pr},
+		},
+		prDetails: map[string]*githubapi.PullRequest{
+			"deseretdigital/example#46": {Number: 46, Title: "Cache warmup", Additions: 7, Deletions: 1, ChangedFiles: 2},
+		},
+	}
+
+	notifier := &fakeNotifier{}
+	mon := NewMonitor(Config{AssignedQuery: "assigned", DraftQuery: "drafts"}, client, notifier, state, nil)
+
+	if err := mon.pollAssigned(ctx); err != nil {
+		t.Fatalf("pollAssigned error = %v", err)
+	}
+	if len(notifier.notifications) != 0 {
+		t.Fatalf("expected no notifications for draft, got %d", len(notifier.notifications))
+	}
+	if !state.DraftPRs["deseretdigital/example#46"] {
+		t.Fatalf("draft status not recorded")
+	}
+
+	pr.IsDraft = false
+	pr.UpdatedAt = readyTime
+	client.searches = map[string][]githubapi.PullRequestSummary{
+		"assigned": {pr},
+		"drafts":   nil,
+	}
+
+	if err := mon.pollAssigned(ctx); err != nil {
+		t.Fatalf("pollAssigned error = %v", err)
+	}
+	if len(notifier.notifications) != 1 {
+		t.Fatalf("expected 1 notification, got %d", len(notifier.notifications))
+	}
+	if got, want := notifier.notifications[0].message, "Ready for review · #46 · +7 −1 · 2 files"; got != want {
+		t.Errorf("notification message = %q, want %q", got, want)
+	}
+	if state.DraftPRs["deseretdigital/example#46"] {
+		t.Errorf("draft status not cleared")
+	}
+}
+
+func TestPollAssignedForgetsClosedDrafts(t *testing.T) {
+	state := cache.NewState()
+	state.Initialized = true
+	state.DraftPRs["deseretdigital/example#47"] = true
+
+	mon := NewMonitor(Config{AssignedQuery: "assigned", DraftQuery: "drafts"}, &fakeGitHubClient{}, &fakeNotifier{}, state, nil)
+	if err := mon.pollAssigned(context.Background()); err != nil {
+		t.Fatalf("pollAssigned error = %v", err)
+	}
+	if len(state.DraftPRs) != 0 {
+		t.Errorf("expected the closed draft to be forgotten, got %v", state.DraftPRs)
+	}
+}
+
+func TestPollAssignedNotifyDrafts(t *testing.T) {
+	state := cache.NewState()
+	state.Initialized = true
+
+	client := &fakeGitHubClient{
+		assigned: []githubapi.PullRequestSummary{
+			{
+				Number:    47,
+				Title:     "Spike: new cache",
+				URL:       "https://github.com/deseretdigital/example/pull/47",
+				UpdatedAt: time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC),
+				IsDraft:   true,
+			},
+		},
+		prDetails: map[string]*githubapi.PullRequest{
+			"deseretdigital/example#47": {Number: 47, Title: "Spike: new cache", Additions: 1, ChangedFiles: 1},
+		},
+	}
+
+	notifier := &fakeNotifier{}
+	mon := NewMonitor(Config{NotifyDrafts: true}, client, notifier, state, nil)
+
+	if err := mon.pollAssigned(context.Background()); err != nil {
+		t.Fatalf("pollAssigned error = %v", err)
+	}
+	if len(notifier.notifications) != 1 {
+		t.Fatalf("expected 1 notification, got %d", len(notifier.notifications))
+	}
+	if got, want := notifier.notifications[0].message, "Review requested on draft · #47 · +1 −0 · 1 files"; got != want {
+		t.Errorf("notification message = %q, want %q", got, want)
+	}
+}
+
+func commitEvent(at time.Time) githubapi.TimelineEvent {
+	ev := githubapi.TimelineEvent{Event: "committed"}
+	ev.Committer.Date = at
+	return ev
+}
+
 func TestPollAssignedInitialSyncSuppressesNotifications(t *testing.T) {
 	ctx := context.Background()
 	state := cache.NewState()
@@ -230,6 +520,9 @@ func TestPollAuthoredNotifiesOnNewCommentAndReview(t *testing.T) {
 	if reviewNotif.message != "lead: Approved — Approved with minor nits." {
 		t.Errorf("review notification message = %q", reviewNotif.message)
 	}
+	if event := notifier.events[1]; event.Kind != notify.KindReview || event.Actor != "lead" || event.ReviewState != "Approved" || !event.OccurredAt.Equal(reviewTime) {
+		t.Errorf("unexpected review event: %+v", event)
+	}
 
 	record := state.AuthoredPRs["deseretdigital/example#99"]
 	if !record.LastIssueComment.Equal(commentTime) {
@@ -239,3 +532,151 @@ func TestPollAuthoredNotifiesOnNewCommentAndReview(t *testing.T) {
 		t.Errorf("LastReview not updated: %v", record.LastReview)
 	}
 }
+
+func TestPollAuthoredNotifiesReadyToMergeOnce(t *testing.T) {
+	ctx := context.Background()
+	state := cache.NewState()
+	state.Initialized = true
+	approval := githubapi.Review{ID: 1, State: "APPROVED", SubmittedAt: time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)}
+	approval.User.Login = "lead"
+	state.AuthoredPRs["deseretdigital/example#100"] = cache.AuthoredRecord{
+		Reviews:        map[int64]string{1: bodyHash("")},
+		ReviewerStates: map[string]string{"lead": "APPROVED"},
+	}
+
+	client := &fakeGitHubClient{
+		authored: []githubapi.PullRequestSummary{
+			{
+				Number:    100,
+				Title:     "Ship it",
+				URL:       "https://github.com/deseretdigital/example/pull/100",
+				UpdatedAt: time.Date(2024, 1, 1, 13, 0, 0, 0, time.UTC),
+			},
+		},
+		reviews: map[string][]githubapi.Review{
+			"deseretdigital/example#100": {approval},
+		},
+		mergeStatuses: map[string]*githubapi.MergeStatus{
+			"deseretdigital/example#100": {
+				ReviewDecision: "REVIEW_REQUIRED",
+				Mergeable:      "MERGEABLE",
+			},
+		},
+	}
+
+	notifier := &fakeNotifier{}
+	mon := NewMonitor(Config{Author: "trixtur"}, client, notifier, state, nil)
+
+	if err := mon.pollAuthored(ctx); err != nil {
+		t.Fatalf("pollAuthored error = %v", err)
+	}
+	if len(notifier.notifications) != 0 {
+		t.Fatalf("expected no notifications before approval, got %d", len(notifier.notifications))
+	}
+
+	client.mergeStatuses["deseretdigital/example#100"] = &githubapi.MergeStatus{
+		ReviewDecision: "APPROVED",
+		Mergeable:      "MERGEABLE",
+		StatusCheckRollup: []githubapi.CheckStatus{
+			{TypeName: "CheckRun", Name: "test", Status: "COMPLETED", Conclusion: "SUCCESS"},
+		},
+	}
+	for i := 0; i < 2; i++ {
+		if err := mon.pollAuthored(ctx); err != nil {
+			t.Fatalf("pollAuthored error = %v", err)
+		}
+	}
+
+	if len(notifier.notifications) != 1 {
+		t.Fatalf("expected 1 notification, got %d", len(notifier.notifications))
+	}
+	got := notifier.notifications[0]
+	if got.message != "Ready to merge · approved · 1 check passed" {
+		t.Errorf("notification message = %q", got.message)
+	}
+	if got.link != "https://github.com/deseretdigital/example/pull/100" {
+		t.Errorf("notification link = %q", got.link)
+	}
+	if !state.AuthoredPRs["deseretdigital/example#100"].ReadyToMerge {
+		t.Errorf("ReadyToMerge not recorded")
+	}
+}
+
+func TestPollAuthoredTracksCommentsByID(t *testing.T) {
+	ctx := context.Background()
+	state := cache.NewState()
+	state.Initialized = true
+
+	commentTime := time.Date(2024, 1, 1, 13, 0, 0, 0, time.UTC)
+	key := "deseretdigital/example#101"
+	first := githubapi.IssueComment{ID: 1, Body: "First!", UpdatedAt: commentTime}
+	first.User.Login = "teammate"
+
+	client := &fakeGitHubClient{
+		authored: []githubapi.PullRequestSummary{
+			{
+				Number:    101,
+				Title:     "Parallel comments",
+				URL:       "https://github.com/deseretdigital/example/pull/101",
+				UpdatedAt: commentTime,
+			},
+		},
+		issueComments: map[string][]githubapi.IssueComment{key: {first}},
+	}
+
+	for _, tc := range []struct {
+		name        string
+		notifyEdits bool
+		want        []string
+	}{
+		{
+			name:        "edits notified",
+			notifyEdits: true,
+			want:        []string{"teammate: First!", "reviewer: Same second", "teammate (edited): First, edited"},
+		},
+		{
+			name: "edits suppressed",
+			want: []string{"teammate: First!", "reviewer: Same second"},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			state.AuthoredPRs = make(map[string]cache.AuthoredRecord)
+			client.issueComments[key] = []githubapi.IssueComment{first}
+
+			notifier := &fakeNotifier{}
+			mon := NewMonitor(Config{Author: "trixtur", NotifyEdits: tc.notifyEdits}, client, notifier, state, nil)
+			if err := mon.pollAuthored(ctx); err != nil {
+				t.Fatalf("pollAuthored error = %v", err)
+			}
+
+			// A second comment in the same second as the first must still be seen.
+			second := githubapi.IssueComment{ID: 2, Body: "Same second", UpdatedAt: commentTime}
+			second.User.Login = "reviewer"
+			client.issueComments[key] = []githubapi.IssueComment{first, second}
+			if err := mon.pollAuthored(ctx); err != nil {
+				t.Fatalf("pollAuthored error = %v", err)
+			}
+
+			edited := first
+			edited.Body = "First, edited"
+			edited.UpdatedAt = commentTime.Add(time.Hour)
+			client.issueComments[key] = []githubapi.IssueComment{edited, second}
+			if err := mon.pollAuthored(ctx); err != nil {
+				t.Fatalf("pollAuthored error = %v", err)
+			}
+
+			var got []string
+			for _, n := range notifier.notifications {
+				got = append(got, n.message)
+			}
+			if len(got) != len(tc.want) {
+				t.Fatalf("notifications = %q, want %q", got, tc.want)
+			}
+			for i := range got {
+				if got[i] != tc.want[i] {
+					t.Errorf("notification %d = %q, want %q", i, got[i], tc.want[i])
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,120 @@
+package monitor
+
+import (
+	"context"
+	"log/slog"
+	"strings"
+
+	"gh-review-notifier/internal/notify"
+)
+
+// pollWatchedRepos notifies about pull requests newly opened in watched
+// repositories and, optionally, drafts there being marked ready. Each
+// repository is seeded silently the first time it is polled.
+func (m *Monitor) pollWatchedRepos(ctx context.Context) error {
+	for _, repo := range m.cfg.WatchedRepos {
+		poller := "repo:" + repo
+		seeding := m.seeding(poller)
+
+		prs, err := m.client.ListRepoPullRequests(ctx, repo, m.cfg.MaxResults)
+		if err != nil {
+			m.logger.Warn("repository pull request list failed", slog.String("repo", repo), slog.String("error", err.Error()))
+			continue
+		}
+
+		mark := m.repoHighWater(repo)
+		highest := mark
+		open := make(map[string]bool, len(prs))
+		for _, item := range prs {
+			key := prKey(repo, item.Number)
+			open[key] = true
+			highest = max(highest, item.Number)
+
+			m.mu.Lock()
+			wasDraft, known := m.state.RepoPRs[key]
+			m.state.RepoPRs[key] = item.IsDraft
+			m.mu.Unlock()
+
+			// A PR at or below the mark was open before but outside the
+			// list; it is recorded without being reported as opened.
+			opened := !known && item.Number > mark
+
+			if seeding || (m.cfg.Author != "" && strings.EqualFold(item.Author.Login, m.cfg.Author)) {
+				continue
+			}
+
+			var reason string
+			switch {
+			case opened && item.IsDraft:
+				reason = "Opened as draft by " + item.Author.Login
+			case opened:
+				reason = "Opened by " + item.Author.Login
+			case known && wasDraft && !item.IsDraft && m.cfg.NotifyWatchedReady:
+				reason = "Ready for review"
+			default:
+				continue
+			}
+
+			details, err := m.client.PullRequestDetails(ctx, repo, item.Number)
+			if err != nil {
+				m.logger.Warn("failed to load PR details", slog.String("repo", repo), slog.Int("number", item.Number), slog.String("error", err.Error()))
+				continue
+			}
+			kind := notify.KindPullRequestOpened
+			if !opened {
+				kind = notify.KindPullRequestUpdated
+			}
+			event := notify.Event{
+				Kind:       kind,
+				Repo:       repo,
+				Number:     item.Number,
+				Title:      details.Title,
+				Summary:    reason,
+				Actor:      item.Author.Login,
+				Diff:       diffStats(details),
+				URL:        details.URL,
+				OccurredAt: details.UpdatedAt,
+				DedupeKey:  dedupeKey(kind, key),
+			}
+			if err := m.notifier.Notify(ctx, event); err != nil {
+				m.logger.Warn("notification failed", slog.String("repo", repo), slog.Int("number", item.Number), slog.String("error", err.Error()))
+			}
+		}
+
+		m.mu.Lock()
+		m.state.RepoHighWater[repo] = highest
+		m.mu.Unlock()
+
+		// Forget pull requests that are no longer open. A truncated list may
+		// omit open PRs, so only prune when everything was returned.
+		if len(prs) < m.cfg.MaxResults {
+			m.mu.Lock()
+			for key := range m.state.RepoPRs {
+				if strings.HasPrefix(key, repo+"#") && !open[key] {
+					delete(m.state.RepoPRs, key)
+				}
+			}
+			m.mu.Unlock()
+		}
+
+		m.markSeeded(poller)
+	}
+	return nil
+}
+
+// repoHighWater returns the highest PR number already seen in repo. Caches
+// written before the mark was kept fall back to the recorded open PRs.
+func (m *Monitor) repoHighWater(repo string) int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if mark, ok := m.state.RepoHighWater[repo]; ok {
+		return mark
+	}
+	mark := 0
+	for key := range m.state.RepoPRs {
+		if prRepo, number, ok := splitPRKey(key); ok && prRepo == repo {
+			mark = max(mark, number)
+		}
+	}
+	return mark
+}
@@ -0,0 +1,124 @@
+package monitor
+
+import (
+	"context"
+	"testing"
+
+	"gh-review-notifier/internal/cache"
+	githubapi "gh-review-notifier/internal/github"
+	"gh-review-notifier/internal/notify"
+)
+
+func TestPollWatchedRepos(t *testing.T) {
+	ctx := context.Background()
+	state := cache.NewState()
+	state.Initialized = true
+
+	repo := "trixtur/tool"
+	existing := githubapi.PullRequestSummary{Number: 1, URL: "https://github.com/trixtur/tool/pull/1", Author: githubapi.User{Login: "old"}}
+	client := &fakeGitHubClient{
+		repoPRs: map[string][]githubapi.PullRequestSummary{repo: {existing}},
+		prDetails: map[string]*githubapi.PullRequest{
+			"trixtur/tool#2": {Number: 2, Title: "Add flag", URL: "https://github.com/trixtur/tool/pull/2", Additions: 12, Deletions: 3, ChangedFiles: 2},
+			"trixtur/tool#3": {Number: 3, Title: "WIP: rewrite", URL: "https://github.com/trixtur/tool/pull/3", Additions: 400, Deletions: 90, ChangedFiles: 14},
+		},
+	}
+
+	notifier := &fakeNotifier{}
+	mon := NewMonitor(Config{Author: "trixtur", WatchedRepos: []string{repo}, NotifyWatchedReady: true}, client, notifier, state, nil)
+
+	// First poll of a repository seeds its open PRs.
+	if err := mon.pollWatchedRepos(ctx); err != nil {
+		t.Fatalf("pollWatchedRepos error = %v", err)
+	}
+	if len(notifier.notifications) != 0 {
+		t.Fatalf("expected no notifications while seeding, got %d", len(notifier.notifications))
+	}
+
+	draft := githubapi.PullRequestSummary{Number: 3, Author: githubapi.User{Login: "bob"}, IsDraft: true}
+	client.repoPRs[repo] = []githubapi.PullRequestSummary{
+		{Number: 2, Author: githubapi.User{Login: "alice"}},
+		draft,
+		{Number: 4, Author: githubapi.User{Login: "trixtur"}},
+	}
+	if err := mon.pollWatchedRepos(ctx); err != nil {
+		t.Fatalf("pollWatchedRepos error = %v", err)
+	}
+
+	draft.IsDraft = false
+	client.repoPRs[repo] = []githubapi.PullRequestSummary{draft}
+	if err := mon.pollWatchedRepos(ctx); err != nil {
+		t.Fatalf("pollWatchedRepos error = %v", err)
+	}
+
+	want := []notification{
+		{title: "Add flag", subtitle: repo, message: "Opened by alice · #2 · +12 −3 · 2 files", link: "https://github.com/trixtur/tool/pull/2"},
+		{title: "WIP: rewrite", subtitle: repo, message: "Opened as draft by bob · #3 · +400 −90 · 14 files", link: "https://github.com/trixtur/tool/pull/3"},
+		{title: "WIP: rewrite", subtitle: repo, message: "Ready for review · #3 · +400 −90 · 14 files", link: "https://github.com/trixtur/tool/pull/3"},
+	}
+	if len(notifier.notifications) != len(want) {
+		t.Fatalf("notifications = %+v, want %+v", notifier.notifications, want)
+	}
+	for i := range want {
+		if notifier.notifications[i] != want[i] {
+			t.Errorf("notification %d = %+v, want %+v", i, notifier.notifications[i], want[i])
+		}
+	}
+	if got := notifier.events[2].Kind; got != notify.KindPullRequestUpdated {
+		t.Errorf("ready event kind = %q, want %q", got, notify.KindPullRequestUpdated)
+	}
+	if len(state.RepoPRs) != 1 {
+		t.Errorf("expected closed PRs to be pruned, got %v", state.RepoPRs)
+	}
+}
+
+func TestPollWatchedReposWindowShift(t *testing.T) {
+	ctx := context.Background()
+	state := cache.NewState()
+	state.Initialized = true
+
+	repo := "trixtur/tool"
+	client := &fakeGitHubClient{
+		repoPRs: map[string][]githubapi.PullRequestSummary{repo: {
+			{Number: 5, Author: githubapi.User{Login: "alice"}},
+			{Number: 4, Author: githubapi.User{Login: "alice"}},
+		}},
+		prDetails: map[string]*githubapi.PullRequest{
+			"trixtur/tool#3": {Number: 3, Title: "Old change", URL: "https://github.com/trixtur/tool/pull/3"},
+			"trixtur/tool#6": {Number: 6, Title: "New change", URL: "https://github.com/trixtur/tool/pull/6"},
+		},
+	}
+
+	notifier := &fakeNotifier{}
+	mon := NewMonitor(Config{Author: "trixtur", WatchedRepos: []string{repo}, MaxResults: 2}, client, notifier, state, nil)
+
+	if err := mon.pollWatchedRepos(ctx); err != nil {
+		t.Fatalf("pollWatchedRepos error = %v", err)
+	}
+
+	// #5 closes and the older #3 slides into the truncated list.
+	client.repoPRs[repo] = []githubapi.PullRequestSummary{
+		{Number: 4, Author: githubapi.User{Login: "alice"}},
+		{Number: 3, Author: githubapi.User{Login: "bob"}},
+	}
+	if err := mon.pollWatchedRepos(ctx); err != nil {
+		t.Fatalf("pollWatchedRepos error = %v", err)
+	}
+	if len(notifier.notifications) != 0 {
+		t.Fatalf("expected no notification for an older PR, got %+v", notifier.notifications)
+	}
+
+	client.repoPRs[repo] = []githubapi.PullRequestSummary{
+		{Number: 6, Author: githubapi.User{Login: "cy"}},
+		{Number: 4, Author: githubapi.User{Login: "alice"}},
+	}
+	if err := mon.pollWatchedRepos(ctx); err != nil {
+		t.Fatalf("pollWatchedRepos error = %v", err)
+	}
+	if len(notifier.notifications) != 1 || notifier.notifications[0].title != "New change" {
+		t.Fatalf("notifications = %+v, want only #6", notifier.notifications)
+	}
+	if got := state.RepoHighWater[repo]; got != 6 {
+		t.Errorf("high-water mark = %d, want 6", got)
+	}
+}
@@ -0,0 +1,92 @@
+package monitor
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	githubapi "gh-review-notifier/internal/github"
+)
+
+const (
+	reviewApproved         = "APPROVED"
+	reviewChangesRequested = "CHANGES_REQUESTED"
+	reviewDismissed        = "DISMISSED"
+)
+
+// reviewTransition is a change in a reviewer's effective review state.
+type reviewTransition struct {
+	reviewer string
+	from     string
+	to       string
+	review   githubapi.Review
+}
+
+// effectiveReviews returns, per reviewer, the latest review that determines
+// their stance on the pull request. Comment-only and pending reviews do not
+// change a reviewer's effective state.
+func effectiveReviews(reviews []githubapi.Review) map[string]githubapi.Review {
+	effective := make(map[string]githubapi.Review)
+	for _, rvw := range reviews {
+		switch strings.ToUpper(rvw.State) {
+		case reviewApproved, reviewChangesRequested, reviewDismissed:
+		default:
+			continue
+		}
+		if rvw.User.Login == "" {
+			continue
+		}
+		if prev, ok := effective[rvw.User.Login]; ok && rvw.SubmittedAt.Before(prev.SubmittedAt) {
+			continue
+		}
+		effective[rvw.User.Login] = rvw
+	}
+	return effective
+}
+
+// reviewTransitions compares previously recorded reviewer states with the
+// current effective reviews and returns the transitions worth reporting:
+// dismissals and resolutions of requested changes.
+func reviewTransitions(prev map[string]string, effective map[string]githubapi.Review) []reviewTransition {
+	var transitions []reviewTransition
+	for reviewer, rvw := range effective {
+		from, to := prev[reviewer], strings.ToUpper(rvw.State)
+		if from == "" || from == to {
+			continue
+		}
+		if to != reviewDismissed && from != reviewChangesRequested {
+			continue
+		}
+		transitions = append(transitions, reviewTransition{reviewer: reviewer, from: from, to: to, review: rvw})
+	}
+	sort.Slice(transitions, func(i, j int) bool {
+		return transitions[i].reviewer < transitions[j].reviewer
+	})
+	return transitions
+}
+
+func reviewerStates(effective map[string]githubapi.Review) map[string]string {
+	states := make(map[string]string, len(effective))
+	for reviewer, rvw := range effective {
+		states[reviewer] = strings.ToUpper(rvw.State)
+	}
+	return states
+}
+
+func blockingReviews(states map[string]string) int {
+	count := 0
+	for _, state := range states {
+		if state == reviewChangesRequested {
+			count++
+		}
+	}
+	return count
+}
+
+func (t reviewTransition) describe(blocking int) string {
+	return fmt.Sprintf("%s: %s → %s · blocking reviews: %d", t.reviewer, reviewStateLabel(t.from), reviewStateLabel(t.to), blocking)
+}
+
+func reviewStateLabel(state string) string {
+	return titleCase(strings.ReplaceAll(state, "_", " "))
+}
@@ -0,0 +1,139 @@
+package monitor
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"gh-review-notifier/internal/cache"
+	githubapi "gh-review-notifier/internal/github"
+)
+
+func testReview(id int64, login, state string, at time.Time) githubapi.Review {
+	rvw := githubapi.Review{ID: id, State: state, SubmittedAt: at}
+	rvw.User.Login = login
+	return rvw
+}
+
+func TestEffectiveReviewsAndTransitions(t *testing.T) {
+	base := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	reviews := []githubapi.Review{
+		testReview(1, "alice", "CHANGES_REQUESTED", base),
+		testReview(2, "alice", "COMMENTED", base.Add(time.Minute)),
+		testReview(3, "alice", "APPROVED", base.Add(2*time.Minute)),
+		testReview(4, "bob", "DISMISSED", base),
+		testReview(5, "carol", "CHANGES_REQUESTED", base),
+		testReview(6, "dave", "COMMENTED", base),
+	}
+
+	effective := effectiveReviews(reviews)
+	states := reviewerStates(effective)
+	want := map[string]string{"alice": "APPROVED", "bob": "DISMISSED", "carol": "CHANGES_REQUESTED"}
+	if len(states) != len(want) {
+		t.Fatalf("reviewerStates = %v, want %v", states, want)
+	}
+	for reviewer, state := range want {
+		if states[reviewer] != state {
+			t.Errorf("state[%s] = %q, want %q", reviewer, states[reviewer], state)
+		}
+	}
+	if got := blockingReviews(states); got != 1 {
+		t.Errorf("blockingReviews = %d, want 1", got)
+	}
+
+	prev := map[string]string{"alice": "CHANGES_REQUESTED", "bob": "APPROVED", "carol": "APPROVED"}
+	transitions := reviewTransitions(prev, effective)
+	if len(transitions) != 2 {
+		t.Fatalf("expected 2 transitions, got %+v", transitions)
+	}
+	if got := transitions[0].describe(1); got != "alice: Changes requested → Approved · blocking reviews: 1" {
+		t.Errorf("alice transition = %q", got)
+	}
+	if got := transitions[1].describe(1); got != "bob: Approved → Dismissed · blocking reviews: 1" {
+		t.Errorf("bob transition = %q", got)
+	}
+}
+
+func TestPollAuthoredReportsDismissal(t *testing.T) {
+	ctx := context.Background()
+	state := cache.NewState()
+	state.Initialized = true
+
+	submitted := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	key := "deseretdigital/example#102"
+	client := &fakeGitHubClient{
+		authored: []githubapi.PullRequestSummary{
+			{
+				Number:    102,
+				Title:     "Risky change",
+				URL:       "https://github.com/deseretdigital/example/pull/102",
+				UpdatedAt: submitted,
+			},
+		},
+		reviews: map[string][]githubapi.Review{
+			key: {testReview(7, "lead", "CHANGES_REQUESTED", submitted)},
+		},
+	}
+
+	notifier := &fakeNotifier{}
+	mon := NewMonitor(Config{Author: "trixtur"}, client, notifier, state, nil)
+	if err := mon.pollAuthored(ctx); err != nil {
+		t.Fatalf("pollAuthored error = %v", err)
+	}
+	if len(notifier.notifications) != 1 {
+		t.Fatalf("expected the new review notification, got %d", len(notifier.notifications))
+	}
+
+	client.reviews[key] = []githubapi.Review{testReview(7, "lead", "DISMISSED", submitted)}
+	if err := mon.pollAuthored(ctx); err != nil {
+		t.Fatalf("pollAuthored error = %v", err)
+	}
+	if len(notifier.notifications) != 2 {
+		t.Fatalf("expected a dismissal notification, got %d total", len(notifier.notifications))
+	}
+	if got, want := notifier.notifications[1].message, "lead: Changes requested → Dismissed · blocking reviews: 0"; got != want {
+		t.Errorf("dismissal message = %q, want %q", got, want)
+	}
+	if got := state.AuthoredPRs[key].ReviewerStates["lead"]; got != "DISMISSED" {
+		t.Errorf("recorded reviewer state = %q", got)
+	}
+}
+
+func TestPollAuthoredResolutionReportedOnce(t *testing.T) {
+	ctx := context.Background()
+	state := cache.NewState()
+	state.Initialized = true
+
+	submitted := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	key := "deseretdigital/example#103"
+	client := &fakeGitHubClient{
+		authored: []githubapi.PullRequestSummary{
+			{
+				Number:    103,
+				Title:     "Follow-up",
+				URL:       "https://github.com/deseretdigital/example/pull/103",
+				UpdatedAt: submitted,
+			},
+		},
+		reviews: map[string][]githubapi.Review{
+			key: {testReview(8, "lead", "CHANGES_REQUESTED", submitted)},
+		},
+	}
+
+	notifier := &fakeNotifier{}
+	mon := NewMonitor(Config{Author: "trixtur"}, client, notifier, state, nil)
+	if err := mon.pollAuthored(ctx); err != nil {
+		t.Fatalf("pollAuthored error = %v", err)
+	}
+
+	client.reviews[key] = append(client.reviews[key], testReview(9, "lead", "APPROVED", submitted.Add(time.Hour)))
+	if err := mon.pollAuthored(ctx); err != nil {
+		t.Fatalf("pollAuthored error = %v", err)
+	}
+	if len(notifier.notifications) != 2 {
+		t.Fatalf("expected 2 notifications, got %d", len(notifier.notifications))
+	}
+	if got, want := notifier.notifications[1].message, "lead: Changes requested → Approved · blocking reviews: 0"; got != want {
+		t.Errorf("resolution message = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,38 @@
+package monitor
+
+import (
+	"fmt"
+	"hash/fnv"
+)
+
+// seenChange classifies a comment or review against the IDs and body hashes
+// recorded for a pull request.
+type seenChange int
+
+const (
+	seenUnchanged seenChange = iota
+	seenNew
+	seenEdited
+)
+
+// trackSeen records the body hash for id and reports how it compares to what
+// was previously stored.
+func trackSeen(seen map[int64]string, id int64, body string) seenChange {
+	hash := bodyHash(body)
+	prev, ok := seen[id]
+	seen[id] = hash
+	switch {
+	case !ok:
+		return seenNew
+	case prev != hash:
+		return seenEdited
+	default:
+		return seenUnchanged
+	}
+}
+
+func bodyHash(body string) string {
+	h := fnv.New64a()
+	h.Write([]byte(body))
+	return fmt.Sprintf("%016x", h.Sum64())
+}
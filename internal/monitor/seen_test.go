@@ -0,0 +1,20 @@
+package monitor
+
+import "testing"
+
+func TestTrackSeen(t *testing.T) {
+	seen := make(map[int64]string)
+
+	if got := trackSeen(seen, 1, "first"); got != seenNew {
+		t.Fatalf("first sighting = %v, want seenNew", got)
+	}
+	if got := trackSeen(seen, 1, "first"); got != seenUnchanged {
+		t.Fatalf("repeat sighting = %v, want seenUnchanged", got)
+	}
+	if got := trackSeen(seen, 1, "first, edited"); got != seenEdited {
+		t.Fatalf("edited body = %v, want seenEdited", got)
+	}
+	if got := trackSeen(seen, 2, "first, edited"); got != seenNew {
+		t.Fatalf("different ID = %v, want seenNew", got)
+	}
+}
@@ -0,0 +1,132 @@
+package monitor
+
+import (
+	"context"
+	"log/slog"
+	"strings"
+
+	"gh-review-notifier/internal/cache"
+	githubapi "gh-review-notifier/internal/github"
+	"gh-review-notifier/internal/notify"
+)
+
+// pollSubscriptions applies the authored-PR tracking to pull requests watched
+// by URL, and reports once when a watched PR is merged or closed, after which
+// it is unwatched. Newly watched PRs are seeded without notifying about their
+// existing activity, and the records of unwatched PRs are dropped.
+func (m *Monitor) pollSubscriptions(ctx context.Context) error {
+	subs, err := cache.LoadSubscriptions(m.cfg.SubscriptionsFile)
+	if err != nil {
+		m.logger.Warn("subscriptions load failed; skipping watched PRs", slog.String("error", err.Error()))
+		return nil
+	}
+
+	watched := make(map[string]bool, len(subs.PullRequests))
+	var finished []string
+	for _, raw := range subs.PullRequests {
+		repo, number, err := githubapi.PullRequestFromURL(raw)
+		if err != nil {
+			m.logger.Warn("invalid watched pull request", slog.String("url", raw), slog.String("error", err.Error()))
+			continue
+		}
+		key := prKey(repo, number)
+		watched[key] = true
+
+		m.mu.Lock()
+		record, known := m.state.AuthoredPRs[key]
+		m.mu.Unlock()
+		if record.State == "MERGED" || record.State == "CLOSED" {
+			continue
+		}
+
+		details, err := m.client.PullRequestDetails(ctx, repo, number)
+		if err != nil {
+			m.logger.Warn("failed to load PR details", slog.String("repo", repo), slog.Int("number", number), slog.String("error", err.Error()))
+			continue
+		}
+		if details.State == "OPEN" && m.cfg.Author != "" && strings.EqualFold(details.Author.Login, m.cfg.Author) {
+			// Already covered by pollAuthored.
+			continue
+		}
+
+		item := githubapi.PullRequestSummary{
+			Number:    details.Number,
+			Title:     details.Title,
+			URL:       details.URL,
+			UpdatedAt: details.UpdatedAt,
+		}
+		if details.State == "MERGED" || details.State == "CLOSED" {
+			if known && m.state.Initialized {
+				message := "Merged · watched PR"
+				if details.State == "CLOSED" {
+					message = "Closed without merging · watched PR"
+				}
+				event := notify.Event{
+					Kind:       notify.KindPullRequestClosed,
+					Repo:       repo,
+					Number:     number,
+					Title:      item.Title,
+					Summary:    message,
+					URL:        item.URL,
+					OccurredAt: details.UpdatedAt,
+					DedupeKey:  dedupeKey(notify.KindPullRequestClosed, key),
+				}
+				if err := m.notifier.Notify(ctx, event); err != nil {
+					m.logger.Warn("notification failed", slog.String("repo", repo), slog.Int("number", number), slog.String("error", err.Error()))
+				}
+			}
+			// Remember the outcome in case the PR cannot be unwatched.
+			record.State = details.State
+			record.Watched = true
+			m.mu.Lock()
+			m.state.AuthoredPRs[key] = record
+			m.mu.Unlock()
+			finished = append(finished, raw)
+			continue
+		}
+
+		m.trackPullRequest(ctx, repo, item, !known)
+		m.mu.Lock()
+		record = m.state.AuthoredPRs[key]
+		record.Watched = true
+		m.state.AuthoredPRs[key] = record
+		m.mu.Unlock()
+	}
+
+	if len(finished) > 0 {
+		if err := m.unwatch(finished); err != nil {
+			m.logger.Warn("failed to unwatch finished PRs", slog.String("error", err.Error()))
+		} else {
+			for _, raw := range finished {
+				if repo, number, err := githubapi.PullRequestFromURL(raw); err == nil {
+					delete(watched, prKey(repo, number))
+				}
+			}
+		}
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for key, record := range m.state.AuthoredPRs {
+		if !record.Watched || watched[key] {
+			continue
+		}
+		delete(m.state.AuthoredPRs, key)
+		delete(m.state.Labels, key)
+		delete(m.state.Threads, key)
+	}
+	return nil
+}
+
+// unwatch removes urls from the subscriptions file. The file is reloaded
+// first so PRs watched since the poll started are kept.
+func (m *Monitor) unwatch(urls []string) error {
+	subs, err := cache.LoadSubscriptions(m.cfg.SubscriptionsFile)
+	if err != nil {
+		return err
+	}
+	for _, raw := range urls {
+		subs.Remove(raw)
+	}
+	return cache.SaveSubscriptions(m.cfg.SubscriptionsFile, subs)
+}
@@ -0,0 +1,133 @@
+package monitor
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"gh-review-notifier/internal/cache"
+	githubapi "gh-review-notifier/internal/github"
+)
+
+func TestPollSubscriptions(t *testing.T) {
+	ctx := context.Background()
+	state := cache.NewState()
+	state.Initialized = true
+
+	path := filepath.Join(t.TempDir(), "subscriptions.json")
+	subs := &cache.Subscriptions{PullRequests: []string{"https://github.com/deseretdigital/example/pull/200"}}
+	if err := cache.SaveSubscriptions(path, subs); err != nil {
+		t.Fatalf("SaveSubscriptions error = %v", err)
+	}
+
+	key := "deseretdigital/example#200"
+	commentTime := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	existing := githubapi.IssueComment{ID: 1, Body: "Old news", UpdatedAt: commentTime}
+	existing.User.Login = "maintainer"
+
+	client := &fakeGitHubClient{
+		prDetails: map[string]*githubapi.PullRequest{
+			key: {
+				Number: 200,
+				Title:  "Release 2.0",
+				URL:    "https://github.com/deseretdigital/example/pull/200",
+				State:  "OPEN",
+				Author: githubapi.User{Login: "maintainer"},
+			},
+		},
+		issueComments: map[string][]githubapi.IssueComment{key: {existing}},
+	}
+
+	notifier := &fakeNotifier{}
+	mon := NewMonitor(Config{Author: "trixtur", SubscriptionsFile: path}, client, notifier, state, nil)
+
+	// Newly watched PRs are seeded silently.
+	if err := mon.pollSubscriptions(ctx); err != nil {
+		t.Fatalf("pollSubscriptions error = %v", err)
+	}
+	if len(notifier.notifications) != 0 {
+		t.Fatalf("expected no notifications while seeding, got %d", len(notifier.notifications))
+	}
+
+	fresh := githubapi.IssueComment{ID: 2, Body: "Tagging tomorrow", UpdatedAt: commentTime.Add(time.Hour)}
+	fresh.User.Login = "maintainer"
+	client.issueComments[key] = []githubapi.IssueComment{existing, fresh}
+	if err := mon.pollSubscriptions(ctx); err != nil {
+		t.Fatalf("pollSubscriptions error = %v", err)
+	}
+
+	client.prDetails[key].State = "MERGED"
+	for i := 0; i < 2; i++ {
+		if err := mon.pollSubscriptions(ctx); err != nil {
+			t.Fatalf("pollSubscriptions error = %v", err)
+		}
+	}
+
+	want := []string{"maintainer: Tagging tomorrow", "Merged · watched PR"}
+	if len(notifier.notifications) != len(want) {
+		t.Fatalf("notifications = %+v, want %q", notifier.notifications, want)
+	}
+	for i := range want {
+		if notifier.notifications[i].message != want[i] {
+			t.Errorf("notification %d = %q, want %q", i, notifier.notifications[i].message, want[i])
+		}
+	}
+	if _, ok := state.AuthoredPRs[key]; ok {
+		t.Error("record of merged watched PR was kept")
+	}
+	reloaded, err := cache.LoadSubscriptions(path)
+	if err != nil {
+		t.Fatalf("LoadSubscriptions error = %v", err)
+	}
+	if len(reloaded.PullRequests) != 0 {
+		t.Errorf("merged PR still watched: %v", reloaded.PullRequests)
+	}
+}
+
+func TestPollSubscriptionsForgetsUnwatchedPRs(t *testing.T) {
+	ctx := context.Background()
+	state := cache.NewState()
+	state.Initialized = true
+	state.AuthoredPRs["deseretdigital/example#200"] = cache.AuthoredRecord{Watched: true}
+	state.Labels["deseretdigital/example#200"] = []string{"bug"}
+	state.AuthoredPRs["deseretdigital/example#201"] = cache.AuthoredRecord{}
+
+	path := filepath.Join(t.TempDir(), "subscriptions.json")
+	if err := cache.SaveSubscriptions(path, &cache.Subscriptions{}); err != nil {
+		t.Fatalf("SaveSubscriptions error = %v", err)
+	}
+
+	mon := NewMonitor(Config{Author: "trixtur", SubscriptionsFile: path}, &fakeGitHubClient{}, &fakeNotifier{}, state, nil)
+	if err := mon.pollSubscriptions(ctx); err != nil {
+		t.Fatalf("pollSubscriptions error = %v", err)
+	}
+	if _, ok := state.AuthoredPRs["deseretdigital/example#200"]; ok {
+		t.Error("record of unwatched PR was kept")
+	}
+	if _, ok := state.Labels["deseretdigital/example#200"]; ok {
+		t.Error("labels of unwatched PR were kept")
+	}
+	if _, ok := state.AuthoredPRs["deseretdigital/example#201"]; !ok {
+		t.Error("authored PR record was dropped")
+	}
+}
+
+func TestPollSubscriptionsSkipsUnreadableFile(t *testing.T) {
+	state := cache.NewState()
+	state.AuthoredPRs["deseretdigital/example#200"] = cache.AuthoredRecord{Watched: true}
+
+	path := filepath.Join(t.TempDir(), "subscriptions.json")
+	if err := os.WriteFile(path, []byte("{"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	mon := NewMonitor(Config{SubscriptionsFile: path}, &fakeGitHubClient{}, &fakeNotifier{}, state, nil)
+	if err := mon.pollSubscriptions(context.Background()); err != nil {
+		t.Fatalf("pollSubscriptions error = %v", err)
+	}
+	if _, ok := state.AuthoredPRs["deseretdigital/example#200"]; !ok {
+		t.Error("watched record dropped while subscriptions were unreadable")
+	}
+}
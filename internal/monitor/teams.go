@@ -0,0 +1,88 @@
+package monitor
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+)
+
+// requestSource describes who a pending review request on an assigned PR is
+// addressed to ("requested from you" or "requested from @org/team"). It
+// reports muted when the only pending requests that concern the user are for
+// muted teams. Teams the user is not on are ignored. An empty label means the
+// source could not be determined.
+func (m *Monitor) requestSource(ctx context.Context, repo string, number int) (string, bool) {
+	reviewers, err := m.client.RequestedReviewers(ctx, repo, number)
+	if err != nil {
+		m.logger.Warn("requested reviewers fetch failed", slog.String("repo", repo), slog.Int("number", number), slog.String("error", err.Error()))
+		return "", false
+	}
+	if reviewers == nil {
+		return "", false
+	}
+	if m.isSelf(reviewers.Users) {
+		return "requested from you", false
+	}
+
+	owner, _, _ := strings.Cut(repo, "/")
+	var teams []string
+	mine := 0
+	for _, team := range reviewers.Teams {
+		name := fmt.Sprintf("%s/%s", owner, team.Slug)
+		if !m.myTeam(ctx, name) {
+			continue
+		}
+		mine++
+		if m.teamMuted(name) {
+			continue
+		}
+		teams = append(teams, "@"+name)
+	}
+	if len(teams) == 0 {
+		return "", mine > 0
+	}
+	return "requested from " + strings.Join(teams, ", "), false
+}
+
+// myTeam reports whether a review request to the team org/slug concerns the
+// user: a team they belong to, or one followed through -team-query. If
+// memberships cannot be loaded, every team counts.
+func (m *Monitor) myTeam(ctx context.Context, name string) bool {
+	for _, term := range strings.Fields(m.cfg.TeamQuery) {
+		if followed, ok := strings.CutPrefix(term, "team-review-requested:"); ok && strings.EqualFold(followed, name) {
+			return true
+		}
+	}
+
+	m.mu.Lock()
+	loaded := m.teamsLoaded
+	m.mu.Unlock()
+	if !loaded {
+		teams, err := m.client.UserTeams(ctx)
+		m.mu.Lock()
+		if err != nil {
+			m.logger.Warn("team memberships fetch failed; treating every team as yours", slog.String("error", err.Error()))
+		} else {
+			m.teams = make(map[string]bool, len(teams))
+			for _, team := range teams {
+				m.teams[strings.ToLower(team)] = true
+			}
+		}
+		m.teamsLoaded = true
+		m.mu.Unlock()
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.teams == nil || m.teams[strings.ToLower(name)]
+}
+
+func (m *Monitor) teamMuted(name string) bool {
+	for _, muted := range m.cfg.MutedTeams {
+		if strings.EqualFold(strings.TrimPrefix(strings.TrimSpace(muted), "@"), name) {
+			return true
+		}
+	}
+	return false
+}
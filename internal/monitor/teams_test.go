@@ -0,0 +1,123 @@
+package monitor
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"gh-review-notifier/internal/cache"
+	githubapi "gh-review-notifier/internal/github"
+)
+
+func TestRequestSource(t *testing.T) {
+	tests := []struct {
+		name      string
+		reviewers *githubapi.RequestedReviewers
+		muted     []string
+		teams     []string
+		label     string
+		isMuted   bool
+	}{
+		{
+			name: "personal request",
+			reviewers: &githubapi.RequestedReviewers{
+				Users: []githubapi.User{{Login: "Trixtur"}},
+				Teams: []githubapi.Team{{Slug: "platform"}},
+			},
+			muted: []string{"deseretdigital/platform"},
+			label: "requested from you",
+		},
+		{
+			name: "team request",
+			reviewers: &githubapi.RequestedReviewers{
+				Teams: []githubapi.Team{{Slug: "platform"}, {Slug: "web"}},
+			},
+			muted: []string{"@deseretdigital/web"},
+			teams: []string{"deseretdigital/platform", "deseretdigital/web"},
+			label: "requested from @deseretdigital/platform",
+		},
+		{
+			name: "muted team only",
+			reviewers: &githubapi.RequestedReviewers{
+				Teams: []githubapi.Team{{Slug: "web"}},
+			},
+			muted:   []string{"deseretdigital/web"},
+			teams:   []string{"DeseretDigital/web"},
+			isMuted: true,
+		},
+		{
+			name: "other teams only",
+			reviewers: &githubapi.RequestedReviewers{
+				Teams: []githubapi.Team{{Slug: "platform"}, {Slug: "web"}},
+			},
+			muted: []string{"deseretdigital/web"},
+			teams: []string{"deseretdigital/mobile"},
+		},
+		{
+			name: "muted team alongside another team",
+			reviewers: &githubapi.RequestedReviewers{
+				Teams: []githubapi.Team{{Slug: "platform"}, {Slug: "web"}},
+			},
+			muted:   []string{"deseretdigital/web"},
+			teams:   []string{"deseretdigital/web"},
+			isMuted: true,
+		},
+		{
+			name:      "no pending requests",
+			reviewers: &githubapi.RequestedReviewers{},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			client := &fakeGitHubClient{
+				reviewers: map[string]*githubapi.RequestedReviewers{"deseretdigital/example#1": tc.reviewers},
+				teams:     tc.teams,
+			}
+			mon := NewMonitor(Config{Author: "trixtur", MutedTeams: tc.muted}, client, &fakeNotifier{}, cache.NewState(), nil)
+			label, muted := mon.requestSource(context.Background(), "deseretdigital/example", 1)
+			if label != tc.label || muted != tc.isMuted {
+				t.Fatalf("requestSource = (%q, %v), want (%q, %v)", label, muted, tc.label, tc.isMuted)
+			}
+		})
+	}
+}
+
+func TestPollAssignedTeamRequest(t *testing.T) {
+	state := cache.NewState()
+	state.Initialized = true
+
+	client := &fakeGitHubClient{
+		searches: map[string][]githubapi.PullRequestSummary{
+			"assigned": nil,
+			"team": {
+				{
+					Number:    48,
+					Title:     "Bump deps",
+					URL:       "https://github.com/deseretdigital/example/pull/48",
+					UpdatedAt: time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC),
+				},
+			},
+		},
+		prDetails: map[string]*githubapi.PullRequest{
+			"deseretdigital/example#48": {Number: 48, Title: "Bump deps", Additions: 4, Deletions: 4, ChangedFiles: 1},
+		},
+		reviewers: map[string]*githubapi.RequestedReviewers{
+			"deseretdigital/example#48": {Teams: []githubapi.Team{{Slug: "platform"}, {Slug: "web"}}},
+		},
+		teams: []string{"deseretdigital/platform"},
+	}
+
+	notifier := &fakeNotifier{}
+	mon := NewMonitor(Config{AssignedQuery: "assigned", TeamQuery: "team", Author: "trixtur"}, client, notifier, state, nil)
+
+	if err := mon.pollAssigned(context.Background()); err != nil {
+		t.Fatalf("pollAssigned error = %v", err)
+	}
+	if len(notifier.notifications) != 1 {
+		t.Fatalf("expected 1 notification, got %d", len(notifier.notifications))
+	}
+	if got, want := notifier.notifications[0].subtitle, "deseretdigital/example · requested from @deseretdigital/platform"; got != want {
+		t.Errorf("notification subtitle = %q, want %q", got, want)
+	}
+}
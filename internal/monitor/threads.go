@@ -0,0 +1,167 @@
+package monitor
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+
+	githubapi "gh-review-notifier/internal/github"
+	"gh-review-notifier/internal/notify"
+)
+
+const threadsPoller = "threads"
+
+// pollReviewedThreads notifies once when every review thread you started on a
+// PR matching ReviewedQuery has been resolved, so you know to re-review.
+// Unresolved threads left on outdated code do not hold the alert back.
+func (m *Monitor) pollReviewedThreads(ctx context.Context) error {
+	results, err := m.client.SearchAssignedPullRequests(ctx, m.cfg.ReviewedQuery, m.cfg.MaxResults)
+	if err != nil {
+		return fmt.Errorf("search reviewed PRs: %w", err)
+	}
+	report := !m.seeding(threadsPoller)
+
+	seen := make(map[string]bool, len(results))
+	for _, item := range results {
+		repo, err := githubapi.RepoFromURL(item.URL)
+		if err != nil {
+			m.logger.Warn("failed to resolve repo from URL", slog.String("url", item.URL), slog.String("error", err.Error()))
+			continue
+		}
+		threads, err := m.client.ReviewThreads(ctx, repo, item.Number)
+		if err != nil {
+			m.logger.Warn("review threads fetch failed", slog.String("repo", repo), slog.Int("number", item.Number), slog.String("error", err.Error()))
+			continue
+		}
+		key := prKey(repo, item.Number)
+		seen[key] = true
+
+		mine, resolved := 0, 0
+		var ids []string
+		for _, thread := range threads {
+			if !strings.EqualFold(thread.Author.Login, m.cfg.Author) {
+				continue
+			}
+			if thread.IsOutdated && !thread.IsResolved {
+				// The code it was left on has changed since.
+				continue
+			}
+			mine++
+			ids = append(ids, thread.ID)
+			if thread.IsResolved {
+				resolved++
+			}
+		}
+		allResolved := mine > 0 && resolved == mine
+
+		m.mu.Lock()
+		record := m.state.Threads[key]
+		wasResolved := record.AllMineResolved
+		record.AllMineResolved = allResolved
+		m.state.Threads[key] = record
+		m.mu.Unlock()
+
+		if !allResolved || wasResolved || !report {
+			continue
+		}
+		message := "Your thread is resolved"
+		if mine > 1 {
+			message = fmt.Sprintf("All %d of your threads are resolved", mine)
+		}
+		event := notify.Event{
+			Kind:       notify.KindThread,
+			Repo:       repo,
+			Number:     item.Number,
+			Title:      item.Title,
+			Summary:    message,
+			URL:        item.URL,
+			OccurredAt: item.UpdatedAt,
+			// A later round of review brings new threads, and so a new key.
+			DedupeKey: dedupeKey(notify.KindThread, key, "resolved", bodyHash(strings.Join(ids, ","))),
+		}
+		if err := m.notifier.Notify(ctx, event); err != nil {
+			m.logger.Warn("notification failed", slog.String("repo", repo), slog.Int("number", item.Number), slog.String("error", err.Error()))
+		}
+	}
+
+	// Forget PRs that no longer match, unless the search may have been
+	// truncated. Records also tracking threads on your own PRs keep those.
+	if len(results) < m.cfg.MaxResults {
+		m.mu.Lock()
+		for key, record := range m.state.Threads {
+			switch {
+			case seen[key]:
+			case record.Resolved == nil:
+				delete(m.state.Threads, key)
+			case record.AllMineResolved:
+				record.AllMineResolved = false
+				m.state.Threads[key] = record
+			}
+		}
+		m.mu.Unlock()
+	}
+
+	m.markSeeded(threadsPoller)
+	return nil
+}
+
+// checkAuthoredThreads notifies when a previously resolved review thread on
+// one of your PRs is unresolved. Threads on PRs seen for the first time are
+// only recorded.
+func (m *Monitor) checkAuthoredThreads(ctx context.Context, repo string, item githubapi.PullRequestSummary) {
+	threads, err := m.client.ReviewThreads(ctx, repo, item.Number)
+	if err != nil {
+		m.logger.Warn("review threads fetch failed", slog.String("repo", repo), slog.Int("number", item.Number), slog.String("error", err.Error()))
+		return
+	}
+	key := prKey(repo, item.Number)
+
+	m.mu.Lock()
+	record := m.state.Threads[key]
+	m.mu.Unlock()
+	report := m.state.Initialized && record.Resolved != nil
+
+	resolved := make(map[string]bool, len(threads))
+	var reopened []githubapi.ReviewThread
+	for _, thread := range threads {
+		resolved[thread.ID] = thread.IsResolved
+		if record.Resolved[thread.ID] && !thread.IsResolved {
+			reopened = append(reopened, thread)
+		}
+	}
+	record.Resolved = resolved
+
+	m.mu.Lock()
+	m.state.Threads[key] = record
+	m.mu.Unlock()
+
+	if !report {
+		return
+	}
+	for _, thread := range reopened {
+		summary := "Thread unresolved"
+		if thread.Path != "" {
+			summary += " on " + thread.Path
+		}
+		link := thread.URL
+		if link == "" {
+			link = item.URL
+		}
+		event := notify.Event{
+			Kind:       notify.KindThread,
+			Repo:       repo,
+			Number:     item.Number,
+			Title:      item.Title,
+			Summary:    summary,
+			Actor:      thread.Author.Login,
+			Body:       summarizeText(thread.Body, 160),
+			URL:        link,
+			OccurredAt: item.UpdatedAt,
+			DedupeKey:  dedupeKey(notify.KindThread, key, thread.ID, "unresolved", item.UpdatedAt.Unix()),
+		}
+		if err := m.notifier.Notify(ctx, event); err != nil {
+			m.logger.Warn("notification failed", slog.String("repo", repo), slog.Int("number", item.Number), slog.String("error", err.Error()))
+		}
+	}
+}
@@ -0,0 +1,141 @@
+package monitor
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"gh-review-notifier/internal/cache"
+	githubapi "gh-review-notifier/internal/github"
+)
+
+func reviewThread(id, author string, resolved bool) githubapi.ReviewThread {
+	return githubapi.ReviewThread{
+		ID:         id,
+		IsResolved: resolved,
+		Path:       "main.go",
+		Author:     githubapi.User{Login: author},
+		Body:       "Please rename this",
+		URL:        "https://github.com/deseretdigital/example/pull/400#discussion_r" + id,
+	}
+}
+
+func TestPollReviewedThreadsAllResolved(t *testing.T) {
+	ctx := context.Background()
+	state := cache.NewState()
+	state.Initialized = true
+	state.Pollers[threadsPoller] = true
+
+	key := "deseretdigital/example#400"
+	client := &fakeGitHubClient{
+		searches: map[string][]githubapi.PullRequestSummary{
+			"reviewed": {{Number: 400, Title: "Rename things", URL: "https://github.com/deseretdigital/example/pull/400"}},
+		},
+		threads: map[string][]githubapi.ReviewThread{
+			key: {
+				reviewThread("1", "trixtur", true),
+				reviewThread("2", "trixtur", false),
+				reviewThread("3", "someone", false),
+				{ID: "4", IsOutdated: true, Author: githubapi.User{Login: "trixtur"}},
+			},
+		},
+	}
+
+	notifier := &fakeNotifier{}
+	mon := NewMonitor(Config{Author: "trixtur", TrackThreads: true, ReviewedQuery: "reviewed"}, client, notifier, state, nil)
+	if err := mon.pollReviewedThreads(ctx); err != nil {
+		t.Fatalf("pollReviewedThreads error = %v", err)
+	}
+	if len(notifier.notifications) != 0 {
+		t.Fatalf("expected no notifications with open threads, got %d", len(notifier.notifications))
+	}
+
+	client.threads[key][1].IsResolved = true
+	for i := 0; i < 2; i++ {
+		if err := mon.pollReviewedThreads(ctx); err != nil {
+			t.Fatalf("pollReviewedThreads error = %v", err)
+		}
+	}
+	if len(notifier.notifications) != 1 {
+		t.Fatalf("expected 1 notification, got %d", len(notifier.notifications))
+	}
+	if got := notifier.notifications[0].message; got != "All 2 of your threads are resolved" {
+		t.Errorf("notification message = %q", got)
+	}
+	if got := notifier.events[0].DedupeKey; !strings.HasPrefix(got, "thread:deseretdigital/example#400:resolved:") {
+		t.Errorf("dedupe key = %q", got)
+	}
+}
+
+func TestPollReviewedThreadsPrunesUnmatched(t *testing.T) {
+	ctx := context.Background()
+	state := cache.NewState()
+	state.Initialized = true
+	state.Pollers[threadsPoller] = true
+	state.Threads["deseretdigital/example#399"] = cache.ThreadRecord{AllMineResolved: true}
+	state.Threads["deseretdigital/example#398"] = cache.ThreadRecord{AllMineResolved: true, Resolved: map[string]bool{"9": true}}
+
+	key := "deseretdigital/example#400"
+	client := &fakeGitHubClient{
+		searches: map[string][]githubapi.PullRequestSummary{
+			"reviewed": {{Number: 400, Title: "Rename things", URL: "https://github.com/deseretdigital/example/pull/400"}},
+		},
+		threads: map[string][]githubapi.ReviewThread{
+			key: {reviewThread("1", "trixtur", false)},
+		},
+	}
+
+	mon := NewMonitor(Config{Author: "trixtur", TrackThreads: true, ReviewedQuery: "reviewed"}, client, &fakeNotifier{}, state, nil)
+	if err := mon.pollReviewedThreads(ctx); err != nil {
+		t.Fatalf("pollReviewedThreads error = %v", err)
+	}
+
+	if _, ok := state.Threads["deseretdigital/example#399"]; ok {
+		t.Error("expected unmatched PR to be pruned")
+	}
+	if record := state.Threads["deseretdigital/example#398"]; record.AllMineResolved || !record.Resolved["9"] {
+		t.Errorf("authored thread record = %+v, want only the reviewed state cleared", record)
+	}
+	if _, ok := state.Threads[key]; !ok {
+		t.Error("expected matching PR to be kept")
+	}
+}
+
+func TestCheckAuthoredThreadsReportsUnresolve(t *testing.T) {
+	ctx := context.Background()
+	state := cache.NewState()
+	state.Initialized = true
+
+	key := "deseretdigital/example#401"
+	updated := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	item := githubapi.PullRequestSummary{Number: 401, Title: "My change", URL: "https://github.com/deseretdigital/example/pull/401", UpdatedAt: updated}
+	client := &fakeGitHubClient{
+		threads: map[string][]githubapi.ReviewThread{
+			key: {reviewThread("9", "lead", true)},
+		},
+	}
+
+	notifier := &fakeNotifier{}
+	mon := NewMonitor(Config{Author: "trixtur", TrackThreads: true}, client, notifier, state, nil)
+	mon.checkAuthoredThreads(ctx, "deseretdigital/example", item)
+	if len(notifier.notifications) != 0 {
+		t.Fatalf("expected first sighting to be recorded silently, got %d", len(notifier.notifications))
+	}
+
+	client.threads[key][0].IsResolved = false
+	mon.checkAuthoredThreads(ctx, "deseretdigital/example", item)
+	if len(notifier.notifications) != 1 {
+		t.Fatalf("expected 1 notification, got %d", len(notifier.notifications))
+	}
+	got := notifier.notifications[0]
+	if got.message != "Thread unresolved on main.go · lead: Please rename this" {
+		t.Errorf("notification message = %q", got.message)
+	}
+	if got.link != "https://github.com/deseretdigital/example/pull/400#discussion_r9" {
+		t.Errorf("notification link = %q", got.link)
+	}
+	if event := notifier.events[0]; event.DedupeKey != "thread:deseretdigital/example#401:9:unresolved:1704110400" || !event.OccurredAt.Equal(updated) {
+		t.Errorf("dedupe key = %q, occurred at %v", event.DedupeKey, event.OccurredAt)
+	}
+}
@@ -0,0 +1,141 @@
+package monitor
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+
+	githubapi "gh-review-notifier/internal/github"
+	"gh-review-notifier/internal/notify"
+)
+
+const workflowsPoller = "workflows"
+
+const (
+	workflowSucceeded = "success"
+	workflowFailed    = "failure"
+)
+
+// pollWorkflows checks the latest completed run of each workflow on the
+// default branch of the watched repositories, notifying when a workflow
+// starts failing and when it recovers.
+func (m *Monitor) pollWorkflows(ctx context.Context) error {
+	seeding := m.seeding(workflowsPoller)
+
+	for _, repo := range m.cfg.WorkflowRepos {
+		branch, err := m.defaultBranch(ctx, repo)
+		if err != nil {
+			m.logger.Warn("default branch lookup failed", slog.String("repo", repo), slog.String("error", err.Error()))
+			continue
+		}
+		runs, err := m.client.CompletedBranchWorkflowRuns(ctx, repo, branch)
+		if err != nil {
+			m.logger.Warn("workflow runs fetch failed", slog.String("repo", repo), slog.String("error", err.Error()))
+			continue
+		}
+
+		for _, run := range latestRunPerWorkflow(runs) {
+			outcome := workflowOutcome(run.Conclusion)
+			key := fmt.Sprintf("%s/workflows/%d", repo, run.WorkflowID)
+
+			m.mu.Lock()
+			previous := m.state.Workflows[key]
+			m.state.Workflows[key] = outcome
+			m.mu.Unlock()
+
+			if seeding || previous == outcome {
+				continue
+			}
+
+			var (
+				title string
+				kind  notify.Kind
+			)
+			switch {
+			case outcome == workflowFailed:
+				title = fmt.Sprintf("%s failing on %s", run.Name, branch)
+				kind = notify.KindWorkflowFailed
+			case previous == workflowFailed:
+				title = fmt.Sprintf("%s recovered on %s", run.Name, branch)
+				kind = notify.KindWorkflowRecovered
+			default:
+				continue
+			}
+			event := notify.Event{
+				Kind:       kind,
+				Repo:       repo,
+				Title:      title,
+				Body:       commitSummary(run),
+				URL:        run.HTMLURL,
+				OccurredAt: run.CreatedAt,
+				DedupeKey:  dedupeKey(kind, key, run.ID),
+			}
+			if err := m.notifier.Notify(ctx, event); err != nil {
+				m.logger.Warn("notification failed", slog.String("repo", repo), slog.Int64("run", run.ID), slog.String("error", err.Error()))
+			}
+		}
+	}
+
+	m.markSeeded(workflowsPoller)
+	return nil
+}
+
+func (m *Monitor) defaultBranch(ctx context.Context, repo string) (string, error) {
+	m.mu.Lock()
+	branch, ok := m.defaultBranches[repo]
+	m.mu.Unlock()
+	if ok {
+		return branch, nil
+	}
+	branch, err := m.client.DefaultBranch(ctx, repo)
+	if err != nil {
+		return "", err
+	}
+	m.mu.Lock()
+	m.defaultBranches[repo] = branch
+	m.mu.Unlock()
+	return branch, nil
+}
+
+// latestRunPerWorkflow keeps, for each workflow, the newest run with a
+// tracked outcome, relying on the API returning runs newest first.
+func latestRunPerWorkflow(runs []githubapi.WorkflowRun) []githubapi.WorkflowRun {
+	seen := make(map[int64]bool)
+	var latest []githubapi.WorkflowRun
+	for _, run := range runs {
+		if seen[run.WorkflowID] || workflowOutcome(run.Conclusion) == "" {
+			continue
+		}
+		seen[run.WorkflowID] = true
+		latest = append(latest, run)
+	}
+	return latest
+}
+
+// workflowOutcome maps a run conclusion to a tracked outcome. Cancelled,
+// skipped and similar conclusions return "" and leave the state unchanged.
+func workflowOutcome(conclusion string) string {
+	switch conclusion {
+	case "success":
+		return workflowSucceeded
+	case "failure", "timed_out", "startup_failure":
+		return workflowFailed
+	default:
+		return ""
+	}
+}
+
+// commitSummary names the head commit's author and its subject. Commit
+// authors are git names rather than GitHub logins, so they are kept out of
+// Event.Actor.
+func commitSummary(run githubapi.WorkflowRun) string {
+	message := summarizeText(run.HeadCommit.Message, 160)
+	author := run.HeadCommit.Author.Name
+	switch {
+	case author == "":
+		return message
+	case message == "":
+		return author
+	}
+	return author + ": " + message
+}
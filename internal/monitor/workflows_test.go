@@ -0,0 +1,86 @@
+package monitor
+
+import (
+	"context"
+	"testing"
+
+	"gh-review-notifier/internal/cache"
+	githubapi "gh-review-notifier/internal/github"
+)
+
+func workflowRun(id, workflowID int64, name, conclusion, author, message string) githubapi.WorkflowRun {
+	run := githubapi.WorkflowRun{
+		ID:         id,
+		WorkflowID: workflowID,
+		Name:       name,
+		Conclusion: conclusion,
+		HTMLURL:    "https://github.com/deseretdigital/example/actions/runs/" + name,
+	}
+	run.HeadCommit.Author.Name = author
+	run.HeadCommit.Message = message
+	return run
+}
+
+func TestLatestRunPerWorkflow(t *testing.T) {
+	runs := []githubapi.WorkflowRun{
+		workflowRun(5, 1, "CI", "cancelled", "", ""),
+		workflowRun(4, 1, "CI", "failure", "", ""),
+		workflowRun(3, 2, "Lint", "success", "", ""),
+		workflowRun(2, 1, "CI", "success", "", ""),
+	}
+	latest := latestRunPerWorkflow(runs)
+	if len(latest) != 2 || latest[0].ID != 4 || latest[1].ID != 3 {
+		t.Fatalf("latestRunPerWorkflow = %+v", latest)
+	}
+}
+
+func TestPollWorkflowsFailureAndRecovery(t *testing.T) {
+	ctx := context.Background()
+	state := cache.NewState()
+	state.Initialized = true
+
+	repo := "deseretdigital/example"
+	client := &fakeGitHubClient{
+		branchRuns: map[string][]githubapi.WorkflowRun{
+			repo: {workflowRun(1, 10, "CI", "success", "Ann", "Add feature")},
+		},
+	}
+
+	notifier := &fakeNotifier{}
+	mon := NewMonitor(Config{WorkflowRepos: []string{repo}}, client, notifier, state, nil)
+
+	// First poll after enabling seeds the cache.
+	if err := mon.pollWorkflows(ctx); err != nil {
+		t.Fatalf("pollWorkflows error = %v", err)
+	}
+
+	client.branchRuns[repo] = []githubapi.WorkflowRun{workflowRun(2, 10, "CI", "failure", "Bob", "Refactor config\n\nDetails")}
+	if err := mon.pollWorkflows(ctx); err != nil {
+		t.Fatalf("pollWorkflows error = %v", err)
+	}
+	// Still failing on a later run: no repeat alert.
+	client.branchRuns[repo] = []githubapi.WorkflowRun{workflowRun(3, 10, "CI", "failure", "Bob", "Try again")}
+	if err := mon.pollWorkflows(ctx); err != nil {
+		t.Fatalf("pollWorkflows error = %v", err)
+	}
+	client.branchRuns[repo] = []githubapi.WorkflowRun{workflowRun(4, 10, "CI", "success", "Cy", "Fix config")}
+	if err := mon.pollWorkflows(ctx); err != nil {
+		t.Fatalf("pollWorkflows error = %v", err)
+	}
+
+	want := []notification{
+		{title: "CI failing on main", subtitle: repo, message: "Bob: Refactor config", link: "https://github.com/deseretdigital/example/actions/runs/CI"},
+		{title: "CI recovered on main", subtitle: repo, message: "Cy: Fix config", link: "https://github.com/deseretdigital/example/actions/runs/CI"},
+	}
+	if len(notifier.notifications) != len(want) {
+		t.Fatalf("notifications = %+v, want %+v", notifier.notifications, want)
+	}
+	for i := range want {
+		if notifier.notifications[i] != want[i] {
+			t.Errorf("notification %d = %+v, want %+v", i, notifier.notifications[i], want[i])
+		}
+	}
+	if got := state.Workflows[repo+"/workflows/10"]; got != workflowSucceeded {
+		t.Errorf("recorded outcome = %q", got)
+	}
+}
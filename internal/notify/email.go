@@ -0,0 +1,336 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"crypto/tls"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	htmltemplate "html/template"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net"
+	"net/mail"
+	"net/smtp"
+	"net/textproto"
+	"os"
+	"strconv"
+	"strings"
+	texttemplate "text/template"
+	"time"
+)
+
+const (
+	emailTimeout = 30 * time.Second
+	// emailIDDomain is the right-hand side of generated Message-IDs.
+	emailIDDomain = "gh-review-notifier"
+)
+
+const defaultEmailText = `{{.Title}}
+{{if .Subtitle}}{{.Subtitle}}
+{{end}}
+{{.Message}}
+{{if .URL}}
+{{.URL}}
+{{end}}`
+
+const defaultEmailHTML = `<!DOCTYPE html>
+<html>
+<body style="font-family: -apple-system, sans-serif;">
+<h3 style="margin-bottom: 4px;">{{if .URL}}<a href="{{.URL}}">{{.Title}}</a>{{else}}{{.Title}}{{end}}</h3>
+{{if .Subtitle}}<div style="color: #57606a;">{{.Subtitle}}</div>{{end}}
+<p style="white-space: pre-wrap;">{{.Message}}</p>
+{{if .URL}}<p><a href="{{.URL}}">Open on GitHub</a></p>{{end}}
+</body>
+</html>
+`
+
+// EmailConfig configures the SMTP notifier.
+type EmailConfig struct {
+	Host string
+	// Port defaults to 587 for STARTTLS, 465 for implicit TLS and 25
+	// without TLS.
+	Port int
+	// Security is "starttls" (the default), "tls" for implicit TLS, or
+	// "none".
+	Security string
+	// Username and Password enable PLAIN authentication.
+	Username string
+	Password string
+	From     string
+	To       []string
+	// TextTemplateFile and HTMLTemplateFile override the built-in body
+	// templates. Both are executed with an EmailData.
+	TextTemplateFile string
+	HTMLTemplateFile string
+	TLSConfig        *tls.Config
+}
+
+// EmailData is passed to the email templates. Title, Subtitle and Message
+// are the event's default text rendering.
+type EmailData struct {
+	Title       string
+	Subtitle    string
+	Message     string
+	URL         string
+	Repo        string
+	Number      int
+	Kind        Kind
+	Actor       string
+	ReviewState string
+	Body        string
+	Diff        *DiffStats
+	OccurredAt  time.Time
+}
+
+type emailNotifier struct {
+	cfg EmailConfig
+	// from and to are the bare envelope addresses.
+	from string
+	to   []string
+	text *texttemplate.Template
+	html *htmltemplate.Template
+}
+
+// NewEmailNotifier returns a notifier that sends a multipart email per
+// notification. Messages about the same PR share a References header so
+// mail clients thread them.
+func NewEmailNotifier(cfg EmailConfig) (Notifier, error) {
+	if cfg.Host == "" || cfg.From == "" || len(cfg.To) == 0 {
+		return nil, errors.New("email: host, from and at least one recipient are required")
+	}
+	from, err := mail.ParseAddress(cfg.From)
+	if err != nil {
+		return nil, fmt.Errorf("email: invalid from address: %w", err)
+	}
+	var to []string
+	for _, raw := range cfg.To {
+		addr, err := mail.ParseAddress(raw)
+		if err != nil {
+			return nil, fmt.Errorf("email: invalid recipient %q: %w", raw, err)
+		}
+		to = append(to, addr.Address)
+	}
+	switch cfg.Security {
+	case "":
+		cfg.Security = "starttls"
+	case "starttls", "tls", "none":
+	default:
+		return nil, fmt.Errorf("email: unknown security mode %q", cfg.Security)
+	}
+	if cfg.Port == 0 {
+		switch cfg.Security {
+		case "starttls":
+			cfg.Port = 587
+		case "tls":
+			cfg.Port = 465
+		default:
+			cfg.Port = 25
+		}
+	}
+
+	textSrc, err := templateSource(cfg.TextTemplateFile, defaultEmailText)
+	if err != nil {
+		return nil, err
+	}
+	htmlSrc, err := templateSource(cfg.HTMLTemplateFile, defaultEmailHTML)
+	if err != nil {
+		return nil, err
+	}
+	text, err := texttemplate.New("text").Parse(textSrc)
+	if err != nil {
+		return nil, fmt.Errorf("email: parse text template: %w", err)
+	}
+	html, err := htmltemplate.New("html").Parse(htmlSrc)
+	if err != nil {
+		return nil, fmt.Errorf("email: parse html template: %w", err)
+	}
+	return &emailNotifier{
+		cfg:  cfg,
+		from: from.Address,
+		to:   to,
+		text: text,
+		html: html,
+	}, nil
+}
+
+func templateSource(path, fallback string) (string, error) {
+	if path == "" {
+		return fallback, nil
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", fmt.Errorf("email: read template: %w", err)
+	}
+	return string(data), nil
+}
+
+func (e *emailNotifier) Notify(ctx context.Context, event Event) error {
+	title, subtitle, message := event.Text()
+	data := EmailData{
+		Title:       strings.TrimSpace(title),
+		Subtitle:    strings.TrimSpace(subtitle),
+		Message:     strings.TrimSpace(message),
+		URL:         strings.TrimSpace(event.URL),
+		Repo:        event.Repo,
+		Number:      event.Number,
+		Kind:        event.Kind,
+		Actor:       event.Actor,
+		ReviewState: event.ReviewState,
+		Body:        event.Body,
+		Diff:        event.Diff,
+		OccurredAt:  event.OccurredAt,
+	}
+	if data.Repo == "" {
+		data.Repo, data.Number = linkSubject(data.URL)
+	}
+
+	msg, err := e.compose(data)
+	if err != nil {
+		return fmt.Errorf("email notification: %w", err)
+	}
+	if err := e.send(ctx, msg); err != nil {
+		return fmt.Errorf("email notification: %w", err)
+	}
+	return nil
+}
+
+// compose renders a multipart/alternative message.
+func (e *emailNotifier) compose(data EmailData) ([]byte, error) {
+	var textBody, htmlBody bytes.Buffer
+	if err := e.text.Execute(&textBody, data); err != nil {
+		return nil, fmt.Errorf("render text template: %w", err)
+	}
+	if err := e.html.Execute(&htmlBody, data); err != nil {
+		return nil, fmt.Errorf("render html template: %w", err)
+	}
+
+	subject := data.Title
+	if data.Repo != "" && data.Number > 0 {
+		subject = fmt.Sprintf("[%s#%d] %s", data.Repo, data.Number, data.Title)
+	}
+
+	var buf bytes.Buffer
+	parts := multipart.NewWriter(&buf)
+	header := func(name, value string) {
+		fmt.Fprintf(&buf, "%s: %s\r\n", name, value)
+	}
+	header("From", e.cfg.From)
+	header("To", strings.Join(e.cfg.To, ", "))
+	header("Subject", mime.QEncoding.Encode("utf-8", subject))
+	header("Date", time.Now().Format(time.RFC1123Z))
+	header("Message-ID", newMessageID())
+	if thread := threadID(data.Repo, data.Number); thread != "" {
+		header("In-Reply-To", thread)
+		header("References", thread)
+	}
+	header("MIME-Version", "1.0")
+	header("Content-Type", "multipart/alternative; boundary="+parts.Boundary())
+	buf.WriteString("\r\n")
+
+	for _, part := range []struct {
+		contentType string
+		body        []byte
+	}{
+		{"text/plain; charset=utf-8", textBody.Bytes()},
+		{"text/html; charset=utf-8", htmlBody.Bytes()},
+	} {
+		w, err := parts.CreatePart(textproto.MIMEHeader{
+			"Content-Type":              {part.contentType},
+			"Content-Transfer-Encoding": {"quoted-printable"},
+		})
+		if err != nil {
+			return nil, err
+		}
+		qp := quotedprintable.NewWriter(w)
+		if _, err := qp.Write(part.body); err != nil {
+			return nil, err
+		}
+		if err := qp.Close(); err != nil {
+			return nil, err
+		}
+	}
+	if err := parts.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// threadID is the Message-ID every notification about a PR refers to.
+func threadID(repo string, number int) string {
+	if repo == "" || number <= 0 {
+		return ""
+	}
+	return fmt.Sprintf("<%s/%d@%s>", repo, number, emailIDDomain)
+}
+
+func newMessageID() string {
+	random := make([]byte, 8)
+	rand.Read(random)
+	return fmt.Sprintf("<%d.%s@%s>", time.Now().UnixNano(), hex.EncodeToString(random), emailIDDomain)
+}
+
+func (e *emailNotifier) send(ctx context.Context, msg []byte) error {
+	addr := net.JoinHostPort(e.cfg.Host, strconv.Itoa(e.cfg.Port))
+	tlsConfig := e.cfg.TLSConfig
+	if tlsConfig == nil {
+		tlsConfig = &tls.Config{ServerName: e.cfg.Host}
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, emailTimeout)
+	defer cancel()
+	var dialer net.Dialer
+	conn, err := dialer.DialContext(ctx, "tcp", addr)
+	if err != nil {
+		return err
+	}
+	if deadline, ok := ctx.Deadline(); ok {
+		conn.SetDeadline(deadline)
+	}
+	if e.cfg.Security == "tls" {
+		conn = tls.Client(conn, tlsConfig)
+	}
+
+	client, err := smtp.NewClient(conn, e.cfg.Host)
+	if err != nil {
+		conn.Close()
+		return err
+	}
+	defer client.Close()
+
+	if e.cfg.Security == "starttls" {
+		if ok, _ := client.Extension("STARTTLS"); !ok {
+			return errors.New("server does not support STARTTLS")
+		}
+		if err := client.StartTLS(tlsConfig); err != nil {
+			return fmt.Errorf("starttls: %w", err)
+		}
+	}
+	if e.cfg.Username != "" {
+		if err := client.Auth(smtp.PlainAuth("", e.cfg.Username, e.cfg.Password, e.cfg.Host)); err != nil {
+			return fmt.Errorf("auth: %w", err)
+		}
+	}
+	if err := client.Mail(e.from); err != nil {
+		return err
+	}
+	for _, rcpt := range e.to {
+		if err := client.Rcpt(rcpt); err != nil {
+			return err
+		}
+	}
+	w, err := client.Data()
+	if err != nil {
+		return err
+	}
+	if _, err := w.Write(msg); err != nil {
+		return err
+	}
+	if err := w.Close(); err != nil {
+		return err
+	}
+	return client.Quit()
+}
@@ -0,0 +1,228 @@
+package notify
+
+import (
+	"bufio"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/base64"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net"
+	"net/http/httptest"
+	"net/mail"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// fakeSMTP is an in-process SMTP server that supports STARTTLS and PLAIN
+// auth and hands each delivered message to the test.
+type fakeSMTP struct {
+	ln        net.Listener
+	tlsConfig *tls.Config
+	messages  chan string
+	auth      chan string
+}
+
+func startFakeSMTP(t *testing.T) (*fakeSMTP, *x509.CertPool) {
+	t.Helper()
+	certServer := httptest.NewUnstartedServer(nil)
+	certServer.StartTLS()
+	t.Cleanup(certServer.Close)
+	roots := x509.NewCertPool()
+	roots.AddCert(certServer.Certificate())
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	t.Cleanup(func() { ln.Close() })
+	s := &fakeSMTP{
+		ln:        ln,
+		tlsConfig: &tls.Config{Certificates: certServer.TLS.Certificates},
+		messages:  make(chan string, 10),
+		auth:      make(chan string, 10),
+	}
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			go s.serve(conn)
+		}
+	}()
+	return s, roots
+}
+
+func (s *fakeSMTP) port() int {
+	return s.ln.Addr().(*net.TCPAddr).Port
+}
+
+func (s *fakeSMTP) serve(conn net.Conn) {
+	defer conn.Close()
+	r := bufio.NewReader(conn)
+	reply := func(line string) { io.WriteString(conn, line+"\r\n") }
+	secure := false
+
+	reply("220 fake ESMTP")
+	for {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			return
+		}
+		cmd := strings.TrimSpace(line)
+		verb := strings.ToUpper(strings.Fields(cmd + " x")[0])
+		switch verb {
+		case "EHLO":
+			if secure {
+				reply("250-fake")
+				reply("250 AUTH PLAIN")
+			} else {
+				reply("250-fake")
+				reply("250 STARTTLS")
+			}
+		case "STARTTLS":
+			reply("220 ready")
+			tlsConn := tls.Server(conn, s.tlsConfig)
+			if err := tlsConn.Handshake(); err != nil {
+				return
+			}
+			conn = tlsConn
+			r = bufio.NewReader(conn)
+			secure = true
+		case "AUTH":
+			decoded, _ := base64.StdEncoding.DecodeString(strings.Fields(cmd)[2])
+			s.auth <- string(decoded)
+			reply("235 ok")
+		case "MAIL", "RCPT", "RSET", "NOOP":
+			reply("250 ok")
+		case "DATA":
+			reply("354 go ahead")
+			var msg strings.Builder
+			for {
+				line, err := r.ReadString('\n')
+				if err != nil {
+					return
+				}
+				if line == ".\r\n" {
+					break
+				}
+				msg.WriteString(strings.TrimPrefix(line, "."))
+			}
+			s.messages <- msg.String()
+			reply("250 queued")
+		case "QUIT":
+			reply("221 bye")
+			return
+		default:
+			reply("502 unknown")
+		}
+	}
+}
+
+func TestEmailNotifierSendsThreadedMultipart(t *testing.T) {
+	server, roots := startFakeSMTP(t)
+	n, err := NewEmailNotifier(EmailConfig{
+		Host:      "127.0.0.1",
+		Port:      server.port(),
+		Username:  "me",
+		Password:  "secret",
+		From:      "Review Bot <bot@example.com>",
+		To:        []string{"me@example.com"},
+		TLSConfig: &tls.Config{ServerName: "127.0.0.1", RootCAs: roots},
+	})
+	if err != nil {
+		t.Fatalf("NewEmailNotifier error = %v", err)
+	}
+
+	link := "https://github.com/deseretdigital/example/pull/42#issuecomment-1"
+	if err := n.Notify(context.Background(), Event{Repo: "deseretdigital/example", Number: 42, Title: "Add <widget>", Summary: "New comment by lead", URL: link}); err != nil {
+		t.Fatalf("Notify error = %v", err)
+	}
+
+	if got := <-server.auth; got != "\x00me\x00secret" {
+		t.Errorf("auth = %q", got)
+	}
+	msg, err := mail.ReadMessage(strings.NewReader(<-server.messages))
+	if err != nil {
+		t.Fatalf("parse message: %v", err)
+	}
+	subject, _ := new(mime.WordDecoder).DecodeHeader(msg.Header.Get("Subject"))
+	if subject != "[deseretdigital/example#42] Add <widget>" {
+		t.Errorf("Subject = %q", subject)
+	}
+	if got := msg.Header.Get("In-Reply-To"); got != "<deseretdigital/example/42@gh-review-notifier>" {
+		t.Errorf("In-Reply-To = %q", got)
+	}
+	if msg.Header.Get("References") != msg.Header.Get("In-Reply-To") || msg.Header.Get("Message-ID") == "" {
+		t.Errorf("unexpected threading headers: %v", msg.Header)
+	}
+
+	_, params, err := mime.ParseMediaType(msg.Header.Get("Content-Type"))
+	if err != nil {
+		t.Fatalf("parse content type: %v", err)
+	}
+	parts := multipart.NewReader(msg.Body, params["boundary"])
+	bodies := map[string]string{}
+	for {
+		part, err := parts.NextPart()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatalf("read part: %v", err)
+		}
+		body, _ := io.ReadAll(part)
+		mediaType, _, _ := mime.ParseMediaType(part.Header.Get("Content-Type"))
+		bodies[mediaType] = string(body)
+	}
+	if !strings.Contains(bodies["text/plain"], "New comment by lead") || !strings.Contains(bodies["text/plain"], link) {
+		t.Errorf("text part = %q", bodies["text/plain"])
+	}
+	if !strings.Contains(bodies["text/html"], "Add &lt;widget&gt;") {
+		t.Errorf("html part should escape the title, got %q", bodies["text/html"])
+	}
+}
+
+func TestEmailNotifierCustomTemplate(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "text.tmpl")
+	if err := os.WriteFile(path, []byte("{{.Repo}} #{{.Number}}: {{.Message}}"), 0o600); err != nil {
+		t.Fatalf("write template: %v", err)
+	}
+	n, err := NewEmailNotifier(EmailConfig{
+		Host:             "127.0.0.1",
+		From:             "bot@example.com",
+		To:               []string{"me@example.com"},
+		TextTemplateFile: path,
+	})
+	if err != nil {
+		t.Fatalf("NewEmailNotifier error = %v", err)
+	}
+	msg, err := n.(*emailNotifier).compose(EmailData{Title: "T", Message: "Approved", Repo: "o/r", Number: 3})
+	if err != nil {
+		t.Fatalf("compose error = %v", err)
+	}
+	if !strings.Contains(string(msg), "o/r #3: Approved") {
+		t.Errorf("custom template not used:\n%s", msg)
+	}
+}
+
+func TestNewEmailNotifierValidates(t *testing.T) {
+	if _, err := NewEmailNotifier(EmailConfig{Host: "smtp.example.com", From: "bot@example.com"}); err == nil {
+		t.Errorf("expected error without recipients")
+	}
+	if _, err := NewEmailNotifier(EmailConfig{Host: "smtp.example.com", From: "bot@example.com", To: []string{"me@example.com"}, Security: "ssl3"}); err == nil {
+		t.Errorf("expected error for unknown security mode")
+	}
+	n, err := NewEmailNotifier(EmailConfig{Host: "smtp.example.com", From: "bot@example.com", To: []string{"me@example.com"}, Security: "tls"})
+	if err != nil {
+		t.Fatalf("NewEmailNotifier error = %v", err)
+	}
+	if got := n.(*emailNotifier).cfg.Port; got != 465 {
+		t.Errorf("default implicit TLS port = %d", got)
+	}
+}
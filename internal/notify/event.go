@@ -0,0 +1,135 @@
+package notify
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Kind classifies what an Event reports.
+type Kind string
+
+const (
+	KindReviewRequested    Kind = "review_requested"
+	KindPullRequestUpdated Kind = "pull_request_updated"
+	KindPullRequestOpened  Kind = "pull_request_opened"
+	KindPullRequestClosed  Kind = "pull_request_closed"
+	KindComment            Kind = "comment"
+	KindReview             Kind = "review"
+	KindReviewState        Kind = "review_state"
+	KindReadyToMerge       Kind = "ready_to_merge"
+	KindMergeQueue         Kind = "merge_queue"
+	KindLabel              Kind = "label"
+	KindThread             Kind = "thread"
+	KindIssueAssigned      Kind = "issue_assigned"
+	KindWorkflowFailed     Kind = "workflow_failed"
+	KindWorkflowRecovered  Kind = "workflow_recovered"
+	KindDeployment         Kind = "deployment"
+)
+
+// kinds lists every Kind, for validating configuration that names them.
+var kinds = []Kind{
+	KindReviewRequested, KindPullRequestUpdated, KindPullRequestOpened, KindPullRequestClosed,
+	KindComment, KindReview, KindReviewState, KindReadyToMerge, KindMergeQueue, KindLabel,
+	KindThread, KindIssueAssigned, KindWorkflowFailed, KindWorkflowRecovered, KindDeployment,
+}
+
+// DiffStats is the size of a pull request.
+type DiffStats struct {
+	Additions    int `json:"additions"`
+	Deletions    int `json:"deletions"`
+	ChangedFiles int `json:"changed_files"`
+}
+
+// Lines is the number of changed lines.
+func (d DiffStats) Lines() int {
+	return d.Additions + d.Deletions
+}
+
+// Event is something the monitor noticed. Backends may render it however
+// suits them; Text gives the plain-text form used by desktop notifications.
+type Event struct {
+	Kind Kind
+	// Repo and Number identify the pull request or issue, if any.
+	Repo   string
+	Number int
+	// Title is the pull request or issue title, or a headline for events
+	// that are not about one, such as a failing workflow.
+	Title string
+	// Context qualifies the subject, e.g. the team a review was requested
+	// from or the workflow run awaiting a deployment.
+	Context string
+	// Summary says what happened, e.g. "Review requested".
+	Summary string
+	// Actor is the login of whoever caused the event.
+	Actor string
+	// Edited marks a comment or review whose body changed since it was
+	// first reported.
+	Edited      bool
+	ReviewState string
+	Diff        *DiffStats
+	// Body is the text written by the actor, such as a comment.
+	Body       string
+	URL        string
+	OccurredAt time.Time
+	// DedupeKey is stable across restarts for the same occurrence, so
+	// receivers can drop duplicates.
+	DedupeKey string
+}
+
+// Text renders the event as a title, subtitle and message.
+func (e Event) Text() (title, subtitle, message string) {
+	subtitle = e.Repo
+	if e.Number > 0 && e.Diff == nil {
+		subtitle = fmt.Sprintf("%s · #%d", e.Repo, e.Number)
+	}
+	subtitle = joinText(subtitle, e.Context)
+
+	message = e.Summary
+	if e.Diff != nil {
+		message = joinText(message, fmt.Sprintf("#%d · +%d −%d · %d files", e.Number, e.Diff.Additions, e.Diff.Deletions, e.Diff.ChangedFiles))
+	}
+	body := e.Body
+	if e.Kind == KindReview && e.ReviewState != "" {
+		body = e.ReviewState
+		if e.Body != "" {
+			body = fmt.Sprintf("%s — %s", e.ReviewState, e.Body)
+		}
+	}
+	if body != "" {
+		if e.Actor != "" {
+			edited := ""
+			if e.Edited {
+				edited = " (edited)"
+			}
+			body = fmt.Sprintf("%s%s: %s", e.Actor, edited, body)
+		}
+		message = joinText(message, body)
+	}
+	return e.Title, subtitle, message
+}
+
+// groupKey identifies the pull request or issue an event is about, falling
+// back to its link without any anchor.
+func (e Event) groupKey() string {
+	if e.Repo != "" && e.Number > 0 {
+		return fmt.Sprintf("%s#%d", e.Repo, e.Number)
+	}
+	key, _, _ := strings.Cut(strings.TrimSpace(e.URL), "#")
+	return key
+}
+
+// critical reports whether the event needs attention soon.
+func (e Event) critical() bool {
+	return e.Kind == KindWorkflowFailed || e.Kind == KindDeployment
+}
+
+func joinText(left, right string) string {
+	switch {
+	case left == "":
+		return right
+	case right == "":
+		return left
+	}
+	return left + " · " + right
+}
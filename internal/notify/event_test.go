@@ -0,0 +1,57 @@
+package notify
+
+import "testing"
+
+func TestEventText(t *testing.T) {
+	tests := []struct {
+		name              string
+		event             Event
+		subtitle, message string
+	}{
+		{
+			name: "review request",
+			event: Event{
+				Kind:    KindReviewRequested,
+				Repo:    "deseretdigital/example",
+				Number:  42,
+				Context: "requested from @deseretdigital/web",
+				Summary: "Review requested",
+				Diff:    &DiffStats{Additions: 40, Deletions: 12, ChangedFiles: 3},
+			},
+			subtitle: "deseretdigital/example · requested from @deseretdigital/web",
+			message:  "Review requested · #42 · +40 −12 · 3 files",
+		},
+		{
+			name:     "edited comment",
+			event:    Event{Kind: KindComment, Repo: "deseretdigital/example", Number: 42, Actor: "lead", Edited: true, Body: "please rename"},
+			subtitle: "deseretdigital/example · #42",
+			message:  "lead (edited): please rename",
+		},
+		{
+			name:     "review without body",
+			event:    Event{Kind: KindReview, Repo: "deseretdigital/example", Number: 42, Actor: "lead", ReviewState: "Approved"},
+			subtitle: "deseretdigital/example · #42",
+			message:  "lead: Approved",
+		},
+		{
+			name:     "unresolved thread",
+			event:    Event{Kind: KindThread, Repo: "deseretdigital/example", Number: 42, Summary: "Thread unresolved on main.go", Actor: "lead", Body: "still broken"},
+			subtitle: "deseretdigital/example · #42",
+			message:  "Thread unresolved on main.go · lead: still broken",
+		},
+		{
+			name:     "workflow",
+			event:    Event{Kind: KindWorkflowFailed, Repo: "deseretdigital/example", Title: "CI failing on main", Body: "Fix build"},
+			subtitle: "deseretdigital/example",
+			message:  "Fix build",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, subtitle, message := tt.event.Text()
+			if subtitle != tt.subtitle || message != tt.message {
+				t.Errorf("Text() = %q, %q; want %q, %q", subtitle, message, tt.subtitle, tt.message)
+			}
+		})
+	}
+}
@@ -0,0 +1,145 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"os/exec"
+	"strconv"
+	"sync"
+	"time"
+)
+
+const (
+	execTimeout     = 30 * time.Second
+	execConcurrency = 4
+	execStderrLimit = 4 << 10
+)
+
+// ExecConfig configures the exec hook notifier.
+type ExecConfig struct {
+	// Command is run with sh -c for every notification.
+	Command string
+	// Timeout bounds each run; Concurrency caps how many runs may be in
+	// flight before Notify waits for one to finish.
+	Timeout     time.Duration
+	Concurrency int
+}
+
+type execNotifier struct {
+	cfg    ExecConfig
+	logger *slog.Logger
+	slots  chan struct{}
+	wg     sync.WaitGroup
+}
+
+// NewExecNotifier returns a notifier that runs a command per notification
+// with the event JSON on stdin and its key fields in GH_NOTIFIER_*
+// environment variables. Commands run in the background until they finish,
+// time out or the context passed to Notify ends; failures and stderr output
+// are logged. Close waits for them to exit.
+func NewExecNotifier(cfg ExecConfig, logger *slog.Logger) (Notifier, error) {
+	if cfg.Command == "" {
+		return nil, errors.New("exec: a command is required")
+	}
+	if cfg.Timeout <= 0 {
+		cfg.Timeout = execTimeout
+	}
+	if cfg.Concurrency <= 0 {
+		cfg.Concurrency = execConcurrency
+	}
+	if logger == nil {
+		logger = slog.New(slog.DiscardHandler)
+	}
+	return &execNotifier{
+		cfg:    cfg,
+		logger: logger,
+		slots:  make(chan struct{}, cfg.Concurrency),
+	}, nil
+}
+
+func (e *execNotifier) Notify(ctx context.Context, ev Event) error {
+	event := newWebhookEvent(ev)
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("encode hook event: %w", err)
+	}
+
+	select {
+	case e.slots <- struct{}{}:
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+	e.wg.Add(1)
+	go func() {
+		defer func() {
+			<-e.slots
+			e.wg.Done()
+		}()
+		e.run(ctx, event, payload)
+	}()
+	return nil
+}
+
+func (e *execNotifier) run(ctx context.Context, event WebhookEvent, payload []byte) {
+	ctx, cancel := context.WithTimeout(ctx, e.cfg.Timeout)
+	defer cancel()
+
+	stderr := &limitedBuffer{limit: execStderrLimit}
+	cmd := exec.CommandContext(ctx, "sh", "-c", e.cfg.Command)
+	cmd.Stdin = bytes.NewReader(payload)
+	cmd.Stderr = stderr
+	cmd.WaitDelay = time.Second
+	cmd.Env = append(os.Environ(),
+		"GH_NOTIFIER_KIND="+string(event.Kind),
+		"GH_NOTIFIER_TITLE="+event.Title,
+		"GH_NOTIFIER_SUBTITLE="+event.Subtitle,
+		"GH_NOTIFIER_MESSAGE="+event.Body,
+		"GH_NOTIFIER_URL="+event.URL,
+		"GH_NOTIFIER_REPO="+event.Repo,
+		"GH_NOTIFIER_NUMBER="+strconv.Itoa(event.Number),
+		"GH_NOTIFIER_ACTOR="+event.Actor,
+	)
+
+	err := cmd.Run()
+	attrs := []any{slog.String("command", e.cfg.Command), slog.String("url", event.URL)}
+	if output := stderr.String(); output != "" {
+		attrs = append(attrs, slog.String("stderr", output))
+	}
+	switch {
+	case ctx.Err() == context.DeadlineExceeded:
+		e.logger.Warn("notification hook timed out", append(attrs, slog.Duration("timeout", e.cfg.Timeout))...)
+	case err != nil:
+		e.logger.Warn("notification hook failed", append(attrs, slog.String("error", err.Error()))...)
+	case stderr.Len() > 0:
+		e.logger.Info("notification hook wrote to stderr", attrs...)
+	}
+}
+
+// Close blocks until all running hooks have exited.
+func (e *execNotifier) Close() error {
+	e.wg.Wait()
+	return nil
+}
+
+// limitedBuffer keeps the first limit bytes written to it and discards the
+// rest.
+type limitedBuffer struct {
+	bytes.Buffer
+	limit int
+}
+
+func (b *limitedBuffer) Write(p []byte) (int, error) {
+	if room := b.limit - b.Len(); room > 0 {
+		if len(p) > room {
+			b.Buffer.Write(p[:room])
+		} else {
+			b.Buffer.Write(p)
+		}
+	}
+	return len(p), nil
+}
@@ -0,0 +1,98 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestExecNotifierPipesEvent(t *testing.T) {
+	dir := t.TempDir()
+	var logs bytes.Buffer
+	logger := slog.New(slog.NewTextHandler(&logs, nil))
+
+	command := `cat > "$OUT/event.json"; printf '%s|%s|%s' "$GH_NOTIFIER_KIND" "$GH_NOTIFIER_REPO" "$GH_NOTIFIER_NUMBER" > "$OUT/env"; echo "played sound" >&2`
+	t.Setenv("OUT", dir)
+	n, err := NewExecNotifier(ExecConfig{Command: command}, logger)
+	if err != nil {
+		t.Fatalf("NewExecNotifier error = %v", err)
+	}
+	link := "https://github.com/deseretdigital/example/pull/42"
+	event := Event{Kind: KindReviewRequested, Repo: "deseretdigital/example", Number: 42, Title: "Add widget", Summary: "Review requested", URL: link}
+	if err := n.Notify(context.Background(), event); err != nil {
+		t.Fatalf("Notify error = %v", err)
+	}
+	n.(io.Closer).Close()
+
+	data, err := os.ReadFile(filepath.Join(dir, "event.json"))
+	if err != nil {
+		t.Fatalf("read event: %v", err)
+	}
+	var got WebhookEvent
+	if err := json.Unmarshal(data, &got); err != nil {
+		t.Fatalf("decode event: %v", err)
+	}
+	if got.Title != "Add widget" || got.URL != link || got.Number != 42 {
+		t.Errorf("unexpected event: %+v", got)
+	}
+	env, _ := os.ReadFile(filepath.Join(dir, "env"))
+	if string(env) != "review_requested|deseretdigital/example|42" {
+		t.Errorf("env = %q", env)
+	}
+	if !strings.Contains(logs.String(), "played sound") {
+		t.Errorf("expected stderr to be logged, got %q", logs.String())
+	}
+}
+
+func TestExecNotifierTimeout(t *testing.T) {
+	var logs bytes.Buffer
+	logger := slog.New(slog.NewTextHandler(&logs, nil))
+	n, err := NewExecNotifier(ExecConfig{Command: "sleep 5", Timeout: 50 * time.Millisecond}, logger)
+	if err != nil {
+		t.Fatalf("NewExecNotifier error = %v", err)
+	}
+	start := time.Now()
+	if err := n.Notify(context.Background(), Event{Title: "Title", Summary: "Message"}); err != nil {
+		t.Fatalf("Notify error = %v", err)
+	}
+	n.(io.Closer).Close()
+	if elapsed := time.Since(start); elapsed > 3*time.Second {
+		t.Errorf("hook was not stopped at its timeout, took %s", elapsed)
+	}
+	if !strings.Contains(logs.String(), "notification hook timed out") {
+		t.Errorf("expected timeout to be logged, got %q", logs.String())
+	}
+}
+
+func TestExecNotifierStopsHooksWithContext(t *testing.T) {
+	n, err := NewExecNotifier(ExecConfig{Command: "sleep 5"}, nil)
+	if err != nil {
+		t.Fatalf("NewExecNotifier error = %v", err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	start := time.Now()
+	if err := n.Notify(ctx, Event{Title: "Title", Summary: "Message"}); err != nil {
+		t.Fatalf("Notify error = %v", err)
+	}
+	cancel()
+	n.(io.Closer).Close()
+	if elapsed := time.Since(start); elapsed > 3*time.Second {
+		t.Errorf("hook outlived its context, took %s", elapsed)
+	}
+}
+
+func TestLimitedBuffer(t *testing.T) {
+	b := &limitedBuffer{limit: 5}
+	b.Write([]byte("abc"))
+	b.Write([]byte("defgh"))
+	if b.String() != "abcde" {
+		t.Errorf("limitedBuffer = %q", b.String())
+	}
+}
@@ -0,0 +1,326 @@
+package notify
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"path"
+	"slices"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Route selects which events a backend receives. Every condition that is
+// set must match; an unset condition matches everything.
+type Route struct {
+	// Notifier names the backend the route applies to.
+	Notifier string `json:"notifier"`
+	// Kinds lists event kinds such as "review_requested", "comment",
+	// "review", "workflow_failed" or "deployment".
+	Kinds []string `json:"kinds,omitempty"`
+	// Repos lists owner/name globs, e.g. "deseretdigital/*".
+	Repos []string `json:"repos,omitempty"`
+	// MinSize and MaxSize bound the PR size in changed lines. Events
+	// without diff stats only match routes without size bounds.
+	MinSize int `json:"min_size,omitempty"`
+	MaxSize int `json:"max_size,omitempty"`
+	// Actors lists GitHub logins whose activity matches.
+	Actors []string `json:"actors,omitempty"`
+	// Hours is a local time window such as "09:00-17:30"; windows may wrap
+	// past midnight.
+	Hours string `json:"hours,omitempty"`
+
+	from, to int
+}
+
+// LoadRoutes reads a JSON array of routes from path.
+func LoadRoutes(path string) ([]Route, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read routes: %w", err)
+	}
+	var routes []Route
+	if err := json.Unmarshal(data, &routes); err != nil {
+		return nil, fmt.Errorf("decode routes: %w", err)
+	}
+	for i := range routes {
+		if err := routes[i].compile(); err != nil {
+			return nil, fmt.Errorf("route %d: %w", i+1, err)
+		}
+	}
+	return routes, nil
+}
+
+func (r *Route) compile() error {
+	if r.Notifier == "" {
+		return errors.New("notifier is required")
+	}
+	for _, kind := range r.Kinds {
+		if !slices.Contains(kinds, Kind(kind)) {
+			return fmt.Errorf("unknown kind %q", kind)
+		}
+	}
+	for _, pattern := range r.Repos {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return fmt.Errorf("invalid repo glob %q: %w", pattern, err)
+		}
+	}
+	if r.Hours == "" {
+		return nil
+	}
+	start, end, ok := strings.Cut(r.Hours, "-")
+	if !ok {
+		return fmt.Errorf("invalid hours %q (expected HH:MM-HH:MM)", r.Hours)
+	}
+	var err error
+	if r.from, err = minuteOfDay(start); err != nil {
+		return err
+	}
+	if r.to, err = minuteOfDay(end); err != nil {
+		return err
+	}
+	return nil
+}
+
+func minuteOfDay(raw string) (int, error) {
+	t, err := time.Parse("15:04", strings.TrimSpace(raw))
+	if err != nil {
+		return 0, fmt.Errorf("invalid time %q (expected HH:MM)", raw)
+	}
+	return t.Hour()*60 + t.Minute(), nil
+}
+
+func (r *Route) matches(facts routeFacts) bool {
+	if len(r.Kinds) > 0 && !slices.Contains(r.Kinds, facts.kind) {
+		return false
+	}
+	if len(r.Actors) > 0 && !slices.ContainsFunc(r.Actors, func(actor string) bool {
+		return strings.EqualFold(actor, facts.actor)
+	}) {
+		return false
+	}
+	if len(r.Repos) > 0 && !slices.ContainsFunc(r.Repos, func(pattern string) bool {
+		ok, _ := path.Match(pattern, facts.repo)
+		return ok
+	}) {
+		return false
+	}
+	if r.MinSize > 0 || r.MaxSize > 0 {
+		if facts.size < 0 || facts.size < r.MinSize || (r.MaxSize > 0 && facts.size > r.MaxSize) {
+			return false
+		}
+	}
+	if r.Hours != "" {
+		minute := facts.at.Hour()*60 + facts.at.Minute()
+		if r.from <= r.to {
+			return minute >= r.from && minute < r.to
+		}
+		return minute >= r.from || minute < r.to
+	}
+	return true
+}
+
+// routeFacts are the properties of a notification that routes match on.
+type routeFacts struct {
+	kind  string
+	repo  string
+	size  int
+	actor string
+	at    time.Time
+}
+
+func factsFor(event Event, at time.Time) routeFacts {
+	facts := routeFacts{kind: string(event.Kind), repo: event.Repo, size: -1, actor: event.Actor, at: at}
+	if event.Diff != nil {
+		facts.size = event.Diff.Lines()
+	}
+	return facts
+}
+
+// fanoutQueueSize is how many events may wait for a backend before further
+// ones are dropped.
+const fanoutQueueSize = 100
+
+// fanoutShutdownTimeout bounds how long Close waits for queued events.
+const fanoutShutdownTimeout = 10 * time.Second
+
+// fanout dispatches each notification to every backend whose routes match.
+// Each backend has its own queue and worker, so a slow one delays only
+// itself.
+type fanout struct {
+	names    []string
+	backends map[string]Notifier
+	routes   map[string][]Route
+	logger   *slog.Logger
+	now      func() time.Time
+	// ctx is what queued events are sent with, so they outlive the poll
+	// that produced them. Close cancels it once shutdownTimeout passes.
+	ctx             context.Context
+	cancel          context.CancelFunc
+	shutdownTimeout time.Duration
+	dropped         atomic.Int64
+
+	mu      sync.Mutex
+	closed  bool
+	queues  map[string]chan Event
+	workers sync.WaitGroup
+}
+
+// NewFanout returns a notifier that sends to several named backends in the
+// background. A backend without routes receives every event; otherwise it
+// receives events matching any of its routes. Failures are logged per
+// backend; Notify itself only fails if an event could not be queued for any
+// backend it was routed to. Events are sent independently of the context
+// passed to Notify; Close waits a bounded time for queued ones to be sent.
+func NewFanout(backends map[string]Notifier, routes []Route, logger *slog.Logger) (Notifier, error) {
+	if logger == nil {
+		logger = slog.New(slog.DiscardHandler)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	f := &fanout{
+		backends:        backends,
+		routes:          map[string][]Route{},
+		logger:          logger,
+		now:             time.Now,
+		ctx:             ctx,
+		cancel:          cancel,
+		shutdownTimeout: fanoutShutdownTimeout,
+		queues:          map[string]chan Event{},
+	}
+	for name := range backends {
+		f.names = append(f.names, name)
+	}
+	slices.Sort(f.names)
+	for _, route := range routes {
+		if err := route.compile(); err != nil {
+			cancel()
+			return nil, err
+		}
+		if _, ok := backends[route.Notifier]; !ok {
+			cancel()
+			return nil, fmt.Errorf("route for unknown notifier %q", route.Notifier)
+		}
+		f.routes[route.Notifier] = append(f.routes[route.Notifier], route)
+	}
+	for _, name := range f.names {
+		queue := make(chan Event, fanoutQueueSize)
+		f.queues[name] = queue
+		f.workers.Add(1)
+		go f.work(name, queue)
+	}
+	return f, nil
+}
+
+func (f *fanout) Notify(ctx context.Context, event Event) error {
+	facts := factsFor(event, f.now())
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.closed {
+		return errors.New("fanout: notifier is closed")
+	}
+	var errs []error
+	selected := 0
+	for _, name := range f.names {
+		if !f.routed(name, facts) {
+			continue
+		}
+		selected++
+		select {
+		case f.queues[name] <- event:
+		default:
+			f.logger.Warn("notification dropped", slog.String("notifier", name), slog.String("kind", string(event.Kind)), slog.String("reason", "queue full"))
+			errs = append(errs, fmt.Errorf("%s: queue full", name))
+		}
+	}
+	if selected > 0 && len(errs) == selected {
+		return errors.Join(errs...)
+	}
+	return nil
+}
+
+// work sends queued events to one backend until its queue is closed. Events
+// still queued once the shutdown timeout has passed are dropped.
+func (f *fanout) work(name string, queue <-chan Event) {
+	defer f.workers.Done()
+	backend := f.backends[name]
+	for event := range queue {
+		if f.ctx.Err() != nil {
+			f.dropped.Add(1)
+			continue
+		}
+		if err := backend.Notify(f.ctx, event); err != nil {
+			f.logger.Warn("notification failed",
+				slog.String("notifier", name),
+				slog.String("kind", string(event.Kind)),
+				slog.String("repo", event.Repo),
+				slog.Int("number", event.Number),
+				slog.String("error", err.Error()),
+			)
+		}
+	}
+}
+
+// Close stops accepting events and waits up to the shutdown timeout for the
+// queued ones to be sent. After that, sends in flight are cancelled and the
+// rest are dropped and counted in a single warning. Finally it closes every
+// backend that holds resources, such as running hooks.
+func (f *fanout) Close() error {
+	f.mu.Lock()
+	if !f.closed {
+		f.closed = true
+		for _, queue := range f.queues {
+			close(queue)
+		}
+	}
+	f.mu.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		f.workers.Wait()
+		close(done)
+	}()
+	timer := time.NewTimer(f.shutdownTimeout)
+	defer timer.Stop()
+	select {
+	case <-done:
+	case <-timer.C:
+		f.cancel()
+		<-done
+	}
+	// Hooks started by the exec backend run with f.ctx, so it is released
+	// only once the backends are closed.
+	defer f.cancel()
+	if dropped := f.dropped.Swap(0); dropped > 0 {
+		f.logger.Warn("notifications dropped at shutdown", slog.Int64("count", dropped))
+	}
+
+	var errs []error
+	for _, name := range f.names {
+		if closer, ok := f.backends[name].(io.Closer); ok {
+			if err := closer.Close(); err != nil {
+				errs = append(errs, fmt.Errorf("%s: %w", name, err))
+			}
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func (f *fanout) routed(name string, facts routeFacts) bool {
+	routes, ok := f.routes[name]
+	if !ok {
+		return true
+	}
+	for i := range routes {
+		if routes[i].matches(facts) {
+			return true
+		}
+	}
+	return false
+}
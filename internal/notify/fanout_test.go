@@ -0,0 +1,261 @@
+package notify
+
+import (
+	"context"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+)
+
+type recordingNotifier struct {
+	mu       sync.Mutex
+	messages []string
+	err      error
+}
+
+func (r *recordingNotifier) Notify(ctx context.Context, event Event) error {
+	_, _, message := event.Text()
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.messages = append(r.messages, message)
+	return r.err
+}
+
+func TestFanoutRoutesAndIsolatesFailures(t *testing.T) {
+	desktop := &recordingNotifier{}
+	slack := &recordingNotifier{err: errors.New("invalid_auth")}
+	ntfy := &recordingNotifier{}
+
+	routes := []Route{
+		{Notifier: "slack", Kinds: []string{"review_requested"}, Repos: []string{"deseretdigital/*"}},
+		{Notifier: "ntfy", Actors: []string{"Lead"}, Hours: "22:00-06:00"},
+	}
+	n, err := NewFanout(map[string]Notifier{"desktop": desktop, "slack": slack, "ntfy": ntfy}, routes, nil)
+	if err != nil {
+		t.Fatalf("NewFanout error = %v", err)
+	}
+	n.(*fanout).now = func() time.Time { return time.Date(2024, 5, 1, 23, 30, 0, 0, time.Local) }
+
+	ctx := context.Background()
+	request := Event{
+		Kind:    KindReviewRequested,
+		Repo:    "deseretdigital/example",
+		Number:  42,
+		Title:   "Add widget",
+		Summary: "Review requested",
+		Diff:    &DiffStats{Additions: 40, Deletions: 12, ChangedFiles: 3},
+		URL:     "https://github.com/deseretdigital/example/pull/42",
+	}
+	if err := n.Notify(ctx, request); err != nil {
+		t.Fatalf("Notify should succeed while desktop works, got %v", err)
+	}
+	comment := Event{
+		Kind:   KindComment,
+		Repo:   "deseretdigital/example",
+		Number: 42,
+		Title:  "Add widget",
+		Actor:  "lead",
+		Edited: true,
+		Body:   "please rename",
+		URL:    "https://github.com/deseretdigital/example/pull/42#issuecomment-9",
+	}
+	if err := n.Notify(ctx, comment); err != nil {
+		t.Fatalf("Notify error = %v", err)
+	}
+	if err := n.(io.Closer).Close(); err != nil {
+		t.Fatalf("Close error = %v", err)
+	}
+
+	if len(desktop.messages) != 2 {
+		t.Errorf("desktop has no routes and should get everything, got %d", len(desktop.messages))
+	}
+	if len(slack.messages) != 1 {
+		t.Errorf("slack should only get the review request, got %v", slack.messages)
+	}
+	if len(ntfy.messages) != 1 || ntfy.messages[0] != "lead (edited): please rename" {
+		t.Errorf("ntfy should only get the comment by lead, got %v", ntfy.messages)
+	}
+}
+
+// blockingNotifier holds every Notify call until release is closed.
+type blockingNotifier struct {
+	started chan struct{}
+	release chan struct{}
+}
+
+func (b *blockingNotifier) Notify(ctx context.Context, event Event) error {
+	b.started <- struct{}{}
+	<-b.release
+	return nil
+}
+
+func TestFanoutDoesNotWaitForSlowBackends(t *testing.T) {
+	slow := &blockingNotifier{started: make(chan struct{}, fanoutQueueSize+1), release: make(chan struct{})}
+	fast := &recordingNotifier{}
+	n, err := NewFanout(map[string]Notifier{"webhook": slow, "desktop": fast}, nil, nil)
+	if err != nil {
+		t.Fatalf("NewFanout error = %v", err)
+	}
+
+	done := make(chan error, 1)
+	go func() { done <- n.Notify(context.Background(), Event{Title: "Title", Summary: "Message"}) }()
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Fatalf("Notify error = %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Notify waited for the slow backend")
+	}
+	<-slow.started
+
+	// Once the slow backend's queue is full, its events are dropped while
+	// the fast backend still gets them.
+	for i := 0; i < fanoutQueueSize+1; i++ {
+		if err := n.Notify(context.Background(), Event{Title: "Title", Summary: "Message"}); err != nil {
+			t.Fatalf("Notify %d error = %v", i, err)
+		}
+	}
+	close(slow.release)
+	if err := n.(io.Closer).Close(); err != nil {
+		t.Fatalf("Close error = %v", err)
+	}
+	if got := len(fast.messages); got != fanoutQueueSize+2 {
+		t.Errorf("fast backend got %d events, want %d", got, fanoutQueueSize+2)
+	}
+	if got := len(slow.started); got != fanoutQueueSize {
+		t.Errorf("slow backend got %d more events, want %d", got, fanoutQueueSize)
+	}
+}
+
+func TestFanoutFailsWhenEveryQueueIsFull(t *testing.T) {
+	slow := &blockingNotifier{started: make(chan struct{}, fanoutQueueSize+1), release: make(chan struct{})}
+	n, err := NewFanout(map[string]Notifier{"webhook": slow}, nil, nil)
+	if err != nil {
+		t.Fatalf("NewFanout error = %v", err)
+	}
+	defer func() {
+		close(slow.release)
+		n.(io.Closer).Close()
+	}()
+
+	if err := n.Notify(context.Background(), Event{Title: "Title"}); err != nil {
+		t.Fatalf("Notify error = %v", err)
+	}
+	<-slow.started
+	for i := 0; i < fanoutQueueSize; i++ {
+		if err := n.Notify(context.Background(), Event{Title: "Title"}); err != nil {
+			t.Fatalf("Notify %d error = %v", i, err)
+		}
+	}
+	if err := n.Notify(context.Background(), Event{Title: "Title"}); err == nil {
+		t.Fatal("expected an error when the only backend's queue is full")
+	}
+}
+
+// stallingNotifier blocks every Notify call until its context is done.
+type stallingNotifier struct {
+	started chan struct{}
+}
+
+func (s *stallingNotifier) Notify(ctx context.Context, event Event) error {
+	s.started <- struct{}{}
+	<-ctx.Done()
+	return ctx.Err()
+}
+
+func TestFanoutCloseOutlivesPollContext(t *testing.T) {
+	desktop := &recordingNotifier{}
+	n, err := NewFanout(map[string]Notifier{"desktop": desktop}, nil, nil)
+	if err != nil {
+		t.Fatalf("NewFanout error = %v", err)
+	}
+
+	// The poll that queued the event has already been cancelled.
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := n.Notify(ctx, Event{Title: "Title", Summary: "Message"}); err != nil {
+		t.Fatalf("Notify error = %v", err)
+	}
+	if err := n.(io.Closer).Close(); err != nil {
+		t.Fatalf("Close error = %v", err)
+	}
+	if len(desktop.messages) != 1 {
+		t.Errorf("expected the queued event to be sent, got %v", desktop.messages)
+	}
+}
+
+func TestFanoutCloseDropsEventsAfterTimeout(t *testing.T) {
+	stalled := &stallingNotifier{started: make(chan struct{}, 1)}
+	n, err := NewFanout(map[string]Notifier{"webhook": stalled}, nil, nil)
+	if err != nil {
+		t.Fatalf("NewFanout error = %v", err)
+	}
+	f := n.(*fanout)
+	f.shutdownTimeout = 10 * time.Millisecond
+
+	for i := 0; i < 3; i++ {
+		if err := n.Notify(context.Background(), Event{Title: "Title"}); err != nil {
+			t.Fatalf("Notify %d error = %v", i, err)
+		}
+	}
+	<-stalled.started
+
+	done := make(chan error, 1)
+	go func() { done <- f.Close() }()
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Fatalf("Close error = %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Close did not give up on the stalled backend")
+	}
+	if got := len(stalled.started); got != 0 {
+		t.Errorf("expected the remaining events to be dropped, %d were sent", got)
+	}
+}
+
+func TestRouteMatchesSizeAndHours(t *testing.T) {
+	route := Route{Notifier: "slack", MaxSize: 100, Hours: "09:00-17:00"}
+	if err := route.compile(); err != nil {
+		t.Fatalf("compile error = %v", err)
+	}
+	noon := time.Date(2024, 5, 1, 12, 0, 0, 0, time.Local)
+	if !route.matches(routeFacts{size: 50, at: noon}) {
+		t.Errorf("expected a small PR at noon to match")
+	}
+	if route.matches(routeFacts{size: 500, at: noon}) {
+		t.Errorf("expected a large PR not to match")
+	}
+	if route.matches(routeFacts{size: -1, at: noon}) {
+		t.Errorf("expected events without diff stats not to match a size bound")
+	}
+	if route.matches(routeFacts{size: 50, at: noon.Add(6 * time.Hour)}) {
+		t.Errorf("expected 18:00 to fall outside the window")
+	}
+}
+
+func TestLoadRoutes(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "routes.json")
+	os.WriteFile(path, []byte(`[{"notifier": "slack", "hours": "9am-5pm"}]`), 0o600)
+	if _, err := LoadRoutes(path); err == nil {
+		t.Errorf("expected an error for malformed hours")
+	}
+	os.WriteFile(path, []byte(`[{"notifier": "slack", "kinds": ["pull_request"]}]`), 0o600)
+	if _, err := LoadRoutes(path); err == nil {
+		t.Errorf("expected an error for an unknown kind")
+	}
+	os.WriteFile(path, []byte(`[{"notifier": "slack", "kinds": ["review"], "hours": "09:00-17:00"}]`), 0o600)
+	routes, err := LoadRoutes(path)
+	if err != nil {
+		t.Fatalf("LoadRoutes error = %v", err)
+	}
+	if len(routes) != 1 || routes[0].from != 9*60 || routes[0].to != 17*60 {
+		t.Errorf("unexpected routes: %+v", routes)
+	}
+}
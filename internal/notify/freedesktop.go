@@ -0,0 +1,221 @@
+package notify
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"os/exec"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/godbus/dbus/v5"
+)
+
+const (
+	notificationsName      = "org.freedesktop.Notifications"
+	notificationsPath      = "/org/freedesktop/Notifications"
+	notificationsInterface = "org.freedesktop.Notifications"
+
+	appName        = "gh-review-notifier"
+	openAction     = "open"
+	defaultAction  = "default"
+	dbusTimeout    = 10 * time.Second
+	serverDefaults = int32(-1)
+)
+
+const (
+	urgencyLow byte = iota
+	urgencyNormal
+	urgencyCritical
+)
+
+var markupEscaper = strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;")
+
+// dbusNotifier shows notifications through the freedesktop notification
+// service on the session bus. Notifications of the same kind about the same
+// PR replace each other, and choosing "Open PR" opens the link with xdg-open.
+type dbusNotifier struct {
+	logger  *slog.Logger
+	connect func() (*dbus.Conn, error)
+	open    func(link string) error
+
+	dialMu sync.Mutex
+
+	mu    sync.Mutex
+	conn  *dbus.Conn
+	ids   map[string]uint32
+	keys  map[uint32]string
+	links map[uint32]string
+}
+
+// NewDBusNotifier returns a notifier for Linux and BSD desktops. The session
+// bus is connected on first use.
+func NewDBusNotifier(logger *slog.Logger) Notifier {
+	return newDBusNotifier(logger, func() (*dbus.Conn, error) { return dbus.ConnectSessionBus() }, openWithXDG)
+}
+
+func newDBusNotifier(logger *slog.Logger, connect func() (*dbus.Conn, error), open func(string) error) *dbusNotifier {
+	return &dbusNotifier{
+		logger:  logger,
+		connect: connect,
+		open:    open,
+		ids:     map[string]uint32{},
+		keys:    map[uint32]string{},
+		links:   map[uint32]string{},
+	}
+}
+
+func (n *dbusNotifier) Notify(ctx context.Context, event Event) error {
+	title, subtitle, message := event.Text()
+	link := strings.TrimSpace(event.URL)
+	title = truncateForNotification(title, 128)
+	subtitle = truncateForNotification(subtitle, 256)
+	message = truncateForNotification(message, 512)
+
+	body := message
+	if subtitle != "" {
+		body = strings.TrimSpace(subtitle + "\n" + message)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, dbusTimeout)
+	defer cancel()
+
+	conn, err := n.connection()
+	if err != nil {
+		return n.fail(err)
+	}
+
+	key := replaceKey(event)
+	actions := []string{}
+	if link != "" {
+		actions = []string{defaultAction, "Open", openAction, "Open PR"}
+	}
+	n.mu.Lock()
+	replaces := n.ids[key]
+	n.mu.Unlock()
+	hints := map[string]dbus.Variant{
+		"urgency": dbus.MakeVariant(urgencyFor(event)),
+	}
+
+	var id uint32
+	err = conn.Object(notificationsName, notificationsPath).CallWithContext(ctx, notificationsInterface+".Notify", 0,
+		appName, replaces, "", title, markupEscaper.Replace(body), actions, hints, serverDefaults).Store(&id)
+	if err != nil {
+		return n.fail(err)
+	}
+	if key == "" {
+		return nil
+	}
+	n.mu.Lock()
+	if replaces != 0 && replaces != id {
+		delete(n.keys, replaces)
+		delete(n.links, replaces)
+	}
+	n.ids[key] = id
+	n.keys[id] = key
+	n.links[id] = link
+	n.mu.Unlock()
+	return nil
+}
+
+// replaceKey groups notifications that replace each other: those of the same
+// kind about the same PR, so a new review does not hide an unread comment.
+func replaceKey(event Event) string {
+	key := event.groupKey()
+	if key == "" {
+		return ""
+	}
+	return string(event.Kind) + " " + key
+}
+
+// connection returns the bus connection, dialing a new one if there is none
+// or the last one dropped.
+func (n *dbusNotifier) connection() (*dbus.Conn, error) {
+	n.dialMu.Lock()
+	defer n.dialMu.Unlock()
+
+	n.mu.Lock()
+	conn := n.conn
+	n.mu.Unlock()
+	if conn != nil && conn.Connected() {
+		return conn, nil
+	}
+
+	conn, err := n.connect()
+	if err != nil {
+		return nil, fmt.Errorf("connect to session bus: %w", err)
+	}
+	if err := conn.AddMatchSignal(dbus.WithMatchInterface(notificationsInterface), dbus.WithMatchObjectPath(notificationsPath)); err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("add match: %w", err)
+	}
+	// The channel is closed along with the connection.
+	signals := make(chan *dbus.Signal, 16)
+	conn.Signal(signals)
+	go func() {
+		for signal := range signals {
+			n.handleSignal(signal)
+		}
+	}()
+
+	n.mu.Lock()
+	n.conn = conn
+	n.mu.Unlock()
+	return conn, nil
+}
+
+func (n *dbusNotifier) fail(err error) error {
+	return fmt.Errorf("dbus notification: %w", err)
+}
+
+// handleSignal opens the PR when its notification's action is invoked and
+// forgets notifications once they are closed.
+func (n *dbusNotifier) handleSignal(signal *dbus.Signal) {
+	if len(signal.Body) < 2 {
+		return
+	}
+	id, _ := signal.Body[0].(uint32)
+	switch signal.Name {
+	case notificationsInterface + ".ActionInvoked":
+		action, _ := signal.Body[1].(string)
+		if action != openAction && action != defaultAction {
+			return
+		}
+		n.mu.Lock()
+		link := n.links[id]
+		n.mu.Unlock()
+		if link == "" {
+			return
+		}
+		if err := n.open(link); err != nil && n.logger != nil {
+			n.logger.Warn("failed to open link", slog.String("url", link), slog.String("error", err.Error()))
+		}
+	case notificationsInterface + ".NotificationClosed":
+		n.mu.Lock()
+		if key, ok := n.keys[id]; ok && n.ids[key] == id {
+			delete(n.ids, key)
+		}
+		delete(n.keys, id)
+		delete(n.links, id)
+		n.mu.Unlock()
+	}
+}
+
+// urgencyFor marks failing workflows and deployments awaiting approval as
+// critical; everything else is normal.
+func urgencyFor(event Event) byte {
+	if event.critical() {
+		return urgencyCritical
+	}
+	return urgencyNormal
+}
+
+func openWithXDG(link string) error {
+	cmd := exec.Command("xdg-open", link)
+	if err := cmd.Start(); err != nil {
+		return err
+	}
+	go cmd.Wait()
+	return nil
+}
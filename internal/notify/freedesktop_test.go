@@ -0,0 +1,232 @@
+package notify
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/godbus/dbus/v5"
+)
+
+const testBusConfig = `<!DOCTYPE busconfig PUBLIC "-//freedesktop//DTD D-Bus Bus Configuration 1.0//EN"
+ "http://www.freedesktop.org/standards/dbus/1.0/busconfig.dtd">
+<busconfig>
+  <type>session</type>
+  <listen>unix:path=%s</listen>
+  <auth>EXTERNAL</auth>
+  <policy context="default">
+    <allow send_destination="*" eavesdrop="true"/>
+    <allow eavesdrop="true"/>
+    <allow own="*"/>
+  </policy>
+</busconfig>
+`
+
+// startTestBus runs a private dbus-daemon and returns its address. Tests
+// are skipped where dbus-daemon is not installed.
+func startTestBus(t *testing.T) string {
+	t.Helper()
+	daemon, err := exec.LookPath("dbus-daemon")
+	if err != nil {
+		t.Skip("dbus-daemon not installed")
+	}
+	dir := t.TempDir()
+	socket := filepath.Join(dir, "bus")
+	config := filepath.Join(dir, "bus.conf")
+	if err := os.WriteFile(config, []byte(fmt.Sprintf(testBusConfig, socket)), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	cmd := exec.Command(daemon, "--nofork", "--config-file="+config)
+	if err := cmd.Start(); err != nil {
+		t.Fatalf("start dbus-daemon: %v", err)
+	}
+	t.Cleanup(func() {
+		cmd.Process.Kill()
+		cmd.Wait()
+	})
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		if _, err := os.Stat(socket); err == nil {
+			return "unix:path=" + socket
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("timed out waiting for dbus-daemon")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+// notifyCall is one Notify call received by fakeNotifications.
+type notifyCall struct {
+	replaces uint32
+	summary  string
+	body     string
+	actions  []string
+	hints    map[string]dbus.Variant
+}
+
+// fakeNotifications stands in for the desktop's notification service.
+type fakeNotifications struct {
+	calls chan notifyCall
+
+	mu     sync.Mutex
+	nextID uint32
+}
+
+func (f *fakeNotifications) Notify(app string, replaces uint32, icon, summary, body string, actions []string, hints map[string]dbus.Variant, timeout int32) (uint32, *dbus.Error) {
+	id := replaces
+	if id == 0 {
+		f.mu.Lock()
+		f.nextID++
+		id = f.nextID
+		f.mu.Unlock()
+	}
+	f.calls <- notifyCall{replaces: replaces, summary: summary, body: body, actions: actions, hints: hints}
+	return id, nil
+}
+
+func (f *fakeNotifications) nextCall(t *testing.T) notifyCall {
+	t.Helper()
+	select {
+	case call := <-f.calls:
+		return call
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for Notify call")
+		return notifyCall{}
+	}
+}
+
+// startNotifications serves fakeNotifications on the bus at address and
+// returns the service connection, used to emit signals.
+func startNotifications(t *testing.T, address string) (*fakeNotifications, *dbus.Conn) {
+	t.Helper()
+	conn, err := dbus.Connect(address)
+	if err != nil {
+		t.Fatalf("connect service: %v", err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	service := &fakeNotifications{calls: make(chan notifyCall, 10)}
+	if err := conn.Export(service, notificationsPath, notificationsInterface); err != nil {
+		t.Fatalf("export: %v", err)
+	}
+	if reply, err := conn.RequestName(notificationsName, dbus.NameFlagDoNotQueue); err != nil || reply != dbus.RequestNameReplyPrimaryOwner {
+		t.Fatalf("request name: %v %v", reply, err)
+	}
+	return service, conn
+}
+
+func TestDBusNotifierReplacesAndOpens(t *testing.T) {
+	ctx := context.Background()
+	address := startTestBus(t)
+	service, serviceConn := startNotifications(t, address)
+	opened := make(chan string, 1)
+	n := newDBusNotifier(nil, func() (*dbus.Conn, error) { return dbus.Connect(address) }, func(link string) error {
+		opened <- link
+		return nil
+	})
+	t.Cleanup(func() {
+		if n.conn != nil {
+			n.conn.Close()
+		}
+	})
+
+	event := Event{
+		Kind:    KindComment,
+		Repo:    "deseretdigital/example",
+		Number:  42,
+		Title:   "Add <widget>",
+		Summary: "New comment by lead",
+		URL:     "https://github.com/deseretdigital/example/pull/42",
+	}
+	if err := n.Notify(ctx, event); err != nil {
+		t.Fatalf("Notify error = %v", err)
+	}
+	call := service.nextCall(t)
+	if call.replaces != 0 {
+		t.Errorf("first replaces_id = %v, want 0", call.replaces)
+	}
+	if call.summary != "Add <widget>" {
+		t.Errorf("summary = %q", call.summary)
+	}
+	if call.body != "deseretdigital/example · #42\nNew comment by lead" {
+		t.Errorf("body = %q", call.body)
+	}
+	if len(call.actions) != 4 || call.actions[2] != openAction || call.actions[3] != "Open PR" {
+		t.Errorf("actions = %v", call.actions)
+	}
+	if got := call.hints["urgency"].Value(); got != urgencyNormal {
+		t.Errorf("urgency = %v, want %v", got, urgencyNormal)
+	}
+
+	// A review does not replace the comment alert.
+	review := event
+	review.Kind, review.Summary = KindReview, "Approved by lead"
+	if err := n.Notify(ctx, review); err != nil {
+		t.Fatalf("Notify error = %v", err)
+	}
+	if got := service.nextCall(t).replaces; got != 0 {
+		t.Errorf("review replaces_id = %v, want 0", got)
+	}
+
+	comment := "https://github.com/deseretdigital/example/pull/42#issuecomment-7"
+	event.Summary, event.URL = "New comment by qa", comment
+	if err := n.Notify(ctx, event); err != nil {
+		t.Fatalf("Notify error = %v", err)
+	}
+	if got := service.nextCall(t).replaces; got != 1 {
+		t.Errorf("second comment replaces_id = %v, want 1", got)
+	}
+
+	if err := serviceConn.Emit(notificationsPath, notificationsInterface+".ActionInvoked", uint32(1), openAction); err != nil {
+		t.Fatalf("emit: %v", err)
+	}
+	select {
+	case link := <-opened:
+		if link != comment {
+			t.Errorf("opened %q, want %q", link, comment)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for link to open")
+	}
+
+	if err := serviceConn.Emit(notificationsPath, notificationsInterface+".NotificationClosed", uint32(1), uint32(2)); err != nil {
+		t.Fatalf("emit: %v", err)
+	}
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		n.mu.Lock()
+		_, tracked := n.keys[1]
+		n.mu.Unlock()
+		if !tracked {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("timed out waiting for closed notification to be forgotten")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	event.Summary = "Merged"
+	if err := n.Notify(ctx, event); err != nil {
+		t.Fatalf("Notify error = %v", err)
+	}
+	if got := service.nextCall(t).replaces; got != 0 {
+		t.Errorf("replaces_id after close = %v, want 0", got)
+	}
+}
+
+func TestUrgencyFor(t *testing.T) {
+	if got := urgencyFor(Event{Kind: KindWorkflowFailed}); got != urgencyCritical {
+		t.Errorf("urgencyFor(failing) = %d", got)
+	}
+	if got := urgencyFor(Event{Kind: KindDeployment}); got != urgencyCritical {
+		t.Errorf("urgencyFor(deployment) = %d", got)
+	}
+	if got := urgencyFor(Event{Kind: KindReviewRequested}); got != urgencyNormal {
+		t.Errorf("urgencyFor(PR) = %d", got)
+	}
+}
@@ -0,0 +1,147 @@
+package notify
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"sync"
+)
+
+// JSONLConfig configures the JSON Lines sink.
+type JSONLConfig struct {
+	// Path is the file events are appended to. Empty or "-" writes to
+	// Writer, or stdout if that is nil.
+	Path   string
+	Writer io.Writer
+	// MaxSize rotates the file once writing another event would take it
+	// past this many bytes, keeping MaxBackups old files as Path.1, Path.2
+	// and so on. Zero disables rotation.
+	MaxSize int64
+	// MaxBackups is the number of rotated files to keep. Zero truncates
+	// Path on rotation instead.
+	MaxBackups int
+}
+
+type jsonlNotifier struct {
+	cfg    JSONLConfig
+	logger *slog.Logger
+
+	mu   sync.Mutex
+	out  io.Writer
+	file *os.File
+	size int64
+}
+
+// NewJSONLNotifier returns a notifier that writes each event as one JSON
+// object per line.
+func NewJSONLNotifier(cfg JSONLConfig, logger *slog.Logger) (Notifier, error) {
+	if cfg.MaxBackups < 0 {
+		return nil, errors.New("jsonl: backups must not be negative")
+	}
+	n := &jsonlNotifier{cfg: cfg, logger: logger}
+	if cfg.Path == "" || cfg.Path == "-" {
+		n.out = cfg.Writer
+		if n.out == nil {
+			n.out = os.Stdout
+		}
+		return n, nil
+	}
+	if err := n.open(); err != nil {
+		return nil, err
+	}
+	return n, nil
+}
+
+func (n *jsonlNotifier) Notify(ctx context.Context, event Event) error {
+	line, err := json.Marshal(newWebhookEvent(event))
+	if err != nil {
+		return fmt.Errorf("encode event: %w", err)
+	}
+	line = append(line, '\n')
+
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	if n.file != nil && n.cfg.MaxSize > 0 && n.size > 0 && n.size+int64(len(line)) > n.cfg.MaxSize {
+		if err := n.rotate(); err != nil {
+			if n.file == nil {
+				return n.fail(err)
+			}
+			// Still appending to Path, just past MaxSize.
+			if n.logger != nil {
+				n.logger.Warn("event log rotation failed", slog.String("path", n.cfg.Path), slog.String("error", err.Error()))
+			}
+		}
+	}
+	if n.out == nil {
+		if err := n.open(); err != nil {
+			return n.fail(err)
+		}
+	}
+	written, err := n.out.Write(line)
+	n.size += int64(written)
+	if err != nil {
+		return n.fail(err)
+	}
+	return nil
+}
+
+func (n *jsonlNotifier) fail(err error) error {
+	return fmt.Errorf("jsonl notification: %w", err)
+}
+
+func (n *jsonlNotifier) open() error {
+	file, err := os.OpenFile(n.cfg.Path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		return fmt.Errorf("open event log: %w", err)
+	}
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return fmt.Errorf("stat event log: %w", err)
+	}
+	n.file = file
+	n.out = file
+	n.size = info.Size()
+	return nil
+}
+
+// rotate shifts Path.N-1 to Path.N down to Path itself, dropping the
+// oldest backup, and starts a new file. Without backups Path is truncated. Whether or not that works, Path is
+// reopened for appending so later events are not written to a closed file.
+func (n *jsonlNotifier) rotate() error {
+	err := n.file.Close()
+	n.file, n.out = nil, nil
+	if err != nil {
+		err = fmt.Errorf("close event log: %w", err)
+	} else {
+		err = n.shiftBackups()
+	}
+	if openErr := n.open(); openErr != nil {
+		return errors.Join(err, openErr)
+	}
+	return err
+}
+
+func (n *jsonlNotifier) shiftBackups() error {
+	if n.cfg.MaxBackups == 0 {
+		if err := os.Truncate(n.cfg.Path, 0); err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("truncate event log: %w", err)
+		}
+		return nil
+	}
+	for i := n.cfg.MaxBackups; i > 0; i-- {
+		src := n.cfg.Path
+		if i > 1 {
+			src = fmt.Sprintf("%s.%d", n.cfg.Path, i-1)
+		}
+		err := os.Rename(src, fmt.Sprintf("%s.%d", n.cfg.Path, i))
+		if err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("rotate event log: %w", err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,127 @@
+package notify
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestJSONLNotifierWritesLines(t *testing.T) {
+	var out bytes.Buffer
+	n, err := NewJSONLNotifier(JSONLConfig{Writer: &out}, nil)
+	if err != nil {
+		t.Fatalf("NewJSONLNotifier error = %v", err)
+	}
+	ctx := context.Background()
+	link := "https://github.com/deseretdigital/example/pull/42"
+	for i := 0; i < 2; i++ {
+		if err := n.Notify(ctx, Event{Repo: "deseretdigital/example", Number: 42, Title: "Add widget", Summary: "Review requested", URL: link}); err != nil {
+			t.Fatalf("Notify error = %v", err)
+		}
+	}
+	if err := n.Notify(ctx, Event{Repo: "deseretdigital/example", Number: 42, Title: "Add widget", Summary: "Approved by lead", URL: link}); err != nil {
+		t.Fatalf("Notify error = %v", err)
+	}
+
+	var events []WebhookEvent
+	scanner := bufio.NewScanner(&out)
+	for scanner.Scan() {
+		var event WebhookEvent
+		if err := json.Unmarshal(scanner.Bytes(), &event); err != nil {
+			t.Fatalf("decode line %q: %v", scanner.Text(), err)
+		}
+		events = append(events, event)
+	}
+	if len(events) != 3 {
+		t.Fatalf("expected 3 lines, got %d", len(events))
+	}
+	if events[0].ID == "" || events[0].ID != events[1].ID {
+		t.Errorf("identical events should share an ID: %q %q", events[0].ID, events[1].ID)
+	}
+	if events[2].ID == events[0].ID {
+		t.Errorf("different events should have different IDs")
+	}
+}
+
+func TestJSONLNotifierRotates(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "events.jsonl")
+	n, err := NewJSONLNotifier(JSONLConfig{Path: path, MaxSize: 300, MaxBackups: 2}, nil)
+	if err != nil {
+		t.Fatalf("NewJSONLNotifier error = %v", err)
+	}
+	for i := 0; i < 6; i++ {
+		if err := n.Notify(context.Background(), Event{Repo: "deseretdigital/example", Number: 42, Title: "Add widget", Summary: "Review requested", URL: "https://github.com/deseretdigital/example/pull/42"}); err != nil {
+			t.Fatalf("Notify error = %v", err)
+		}
+	}
+
+	for _, name := range []string{path, path + ".1", path + ".2"} {
+		info, err := os.Stat(name)
+		if err != nil {
+			t.Fatalf("expected %s to exist: %v", name, err)
+		}
+		if info.Size() > 300 {
+			t.Errorf("%s is %d bytes, over the limit", name, info.Size())
+		}
+	}
+	if _, err := os.Stat(path + ".3"); !os.IsNotExist(err) {
+		t.Errorf("expected at most 2 backups, stat .3 = %v", err)
+	}
+}
+
+func TestJSONLNotifierTruncatesWithoutBackups(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "events.jsonl")
+	n, err := NewJSONLNotifier(JSONLConfig{Path: path, MaxSize: 300, MaxBackups: 0}, nil)
+	if err != nil {
+		t.Fatalf("NewJSONLNotifier error = %v", err)
+	}
+	for i := 0; i < 6; i++ {
+		if err := n.Notify(context.Background(), Event{Repo: "deseretdigital/example", Number: 42, Title: "Add widget", Summary: "Review requested", URL: "https://github.com/deseretdigital/example/pull/42"}); err != nil {
+			t.Fatalf("Notify error = %v", err)
+		}
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatalf("stat event log: %v", err)
+	}
+	if info.Size() > 300 {
+		t.Errorf("%s is %d bytes, over the limit", path, info.Size())
+	}
+	if _, err := os.Stat(path + ".1"); !os.IsNotExist(err) {
+		t.Errorf("expected no backups, stat .1 = %v", err)
+	}
+
+	if _, err := NewJSONLNotifier(JSONLConfig{Path: path, MaxBackups: -1}, nil); err == nil {
+		t.Error("expected an error for negative backups")
+	}
+}
+
+func TestJSONLNotifierKeepsWritingWhenRotationFails(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "events.jsonl")
+	// A directory in the way of the backup makes the rename fail.
+	if err := os.MkdirAll(filepath.Join(path+".1", "taken"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	n, err := NewJSONLNotifier(JSONLConfig{Path: path, MaxSize: 100, MaxBackups: 1}, nil)
+	if err != nil {
+		t.Fatalf("NewJSONLNotifier error = %v", err)
+	}
+	for i := 0; i < 3; i++ {
+		if err := n.Notify(context.Background(), Event{Repo: "deseretdigital/example", Number: 42, Title: "Add widget", Summary: "Review requested"}); err != nil {
+			t.Fatalf("Notify %d error = %v", i, err)
+		}
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("read event log: %v", err)
+	}
+	if lines := bytes.Count(data, []byte("\n")); lines != 3 {
+		t.Errorf("expected 3 lines in %s, got %d", path, lines)
+	}
+}
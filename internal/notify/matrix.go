@@ -0,0 +1,214 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"html"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	matrixTimeout     = 10 * time.Second
+	matrixMaxAttempts = 3
+	matrixEditWindow  = time.Hour
+)
+
+// MatrixConfig configures the Matrix room notifier.
+type MatrixConfig struct {
+	// Homeserver is the client-server API base URL, e.g.
+	// https://matrix.example.org.
+	Homeserver string
+	RoomID     string
+	Token      string
+	// EditWindow is how long after a PR's message later notifications edit
+	// it instead of posting a new one. Defaults to an hour.
+	EditWindow time.Duration
+	HTTPClient *http.Client
+}
+
+type matrixNotifier struct {
+	cfg    MatrixConfig
+	client *http.Client
+	txn    atomic.Int64
+
+	mu      sync.Mutex
+	threads map[string]matrixThread
+}
+
+// matrixThread is the message a PR's notifications are edited into.
+type matrixThread struct {
+	eventID string
+	sentAt  time.Time
+}
+
+// NewMatrixNotifier returns a notifier that posts to a Matrix room.
+func NewMatrixNotifier(cfg MatrixConfig) (Notifier, error) {
+	if cfg.Homeserver == "" || cfg.RoomID == "" || cfg.Token == "" {
+		return nil, errors.New("matrix: homeserver, room and access token are required")
+	}
+	if cfg.EditWindow <= 0 {
+		cfg.EditWindow = matrixEditWindow
+	}
+	client := cfg.HTTPClient
+	if client == nil {
+		client = &http.Client{Timeout: matrixTimeout}
+	}
+	n := &matrixNotifier{
+		cfg:     cfg,
+		client:  client,
+		threads: map[string]matrixThread{},
+	}
+	n.txn.Store(time.Now().UnixNano())
+	return n, nil
+}
+
+type matrixContent struct {
+	MsgType       string          `json:"msgtype"`
+	Body          string          `json:"body"`
+	Format        string          `json:"format,omitempty"`
+	FormattedBody string          `json:"formatted_body,omitempty"`
+	NewContent    *matrixContent  `json:"m.new_content,omitempty"`
+	RelatesTo     *matrixRelation `json:"m.relates_to,omitempty"`
+}
+
+type matrixRelation struct {
+	RelType string `json:"rel_type"`
+	EventID string `json:"event_id"`
+}
+
+func (n *matrixNotifier) Notify(ctx context.Context, event Event) error {
+	title, subtitle, message := event.Text()
+	content := matrixMessage(title, subtitle, message, event.URL)
+
+	key := event.groupKey()
+	n.mu.Lock()
+	thread, ok := n.threads[key]
+	n.mu.Unlock()
+	editing := key != "" && ok && time.Since(thread.sentAt) < n.cfg.EditWindow
+	if editing {
+		replacement := content
+		content = matrixContent{
+			MsgType:       replacement.MsgType,
+			Body:          "* " + replacement.Body,
+			Format:        replacement.Format,
+			FormattedBody: "* " + replacement.FormattedBody,
+			NewContent:    &replacement,
+			RelatesTo:     &matrixRelation{RelType: "m.replace", EventID: thread.eventID},
+		}
+	}
+
+	eventID, err := n.send(ctx, content)
+	if err != nil {
+		return fmt.Errorf("matrix notification: %w", err)
+	}
+	if key != "" && !editing {
+		n.mu.Lock()
+		n.threads[key] = matrixThread{eventID: eventID, sentAt: time.Now()}
+		n.mu.Unlock()
+	}
+	return nil
+}
+
+func matrixMessage(title, subtitle, message, link string) matrixContent {
+	title = strings.TrimSpace(title)
+	subtitle = strings.TrimSpace(subtitle)
+	message = strings.TrimSpace(message)
+	link = strings.TrimSpace(link)
+
+	var plain, formatted []string
+	heading := "<b>" + html.EscapeString(title) + "</b>"
+	if link != "" {
+		heading = fmt.Sprintf(`<b><a href="%s">%s</a></b>`, html.EscapeString(link), html.EscapeString(title))
+	}
+	plain = append(plain, title)
+	formatted = append(formatted, heading)
+	for _, line := range []string{subtitle, message} {
+		if line != "" {
+			plain = append(plain, line)
+			formatted = append(formatted, html.EscapeString(line))
+		}
+	}
+	if link != "" {
+		plain = append(plain, link)
+	}
+	return matrixContent{
+		MsgType:       "m.text",
+		Body:          strings.Join(plain, "\n"),
+		Format:        "org.matrix.custom.html",
+		FormattedBody: strings.Join(formatted, "<br>"),
+	}
+}
+
+// send posts an event, retrying when the homeserver rate limits us, and
+// returns its event ID.
+func (n *matrixNotifier) send(ctx context.Context, content matrixContent) (string, error) {
+	payload, err := json.Marshal(content)
+	if err != nil {
+		return "", err
+	}
+	// The transaction ID stays the same across retries so the homeserver
+	// can deduplicate them.
+	endpoint := fmt.Sprintf("%s/_matrix/client/v3/rooms/%s/send/m.room.message/%d",
+		strings.TrimSuffix(n.cfg.Homeserver, "/"), url.PathEscape(n.cfg.RoomID), n.txn.Add(1))
+
+	for attempt := 1; ; attempt++ {
+		eventID, wait, err := n.put(ctx, endpoint, payload)
+		if err == nil {
+			return eventID, nil
+		}
+		if wait <= 0 || attempt == matrixMaxAttempts {
+			return "", err
+		}
+		if err := sleep(ctx, wait); err != nil {
+			return "", err
+		}
+	}
+}
+
+func (n *matrixNotifier) put(ctx context.Context, endpoint string, payload []byte) (string, time.Duration, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, endpoint, bytes.NewReader(payload))
+	if err != nil {
+		return "", 0, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+n.cfg.Token)
+	resp, err := n.client.Do(req)
+	if err != nil {
+		return "", 0, err
+	}
+	defer resp.Body.Close()
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, 64<<10))
+
+	var result struct {
+		EventID      string `json:"event_id"`
+		ErrCode      string `json:"errcode"`
+		Error        string `json:"error"`
+		RetryAfterMS int64  `json:"retry_after_ms"`
+	}
+	json.Unmarshal(body, &result)
+	switch {
+	case resp.StatusCode == http.StatusTooManyRequests:
+		wait := time.Duration(result.RetryAfterMS) * time.Millisecond
+		if wait <= 0 {
+			wait = retryAfter(resp.Header.Get("Retry-After"))
+		}
+		return "", wait, fmt.Errorf("%s: rate limited", resp.Status)
+	case resp.StatusCode >= 300:
+		if result.ErrCode != "" {
+			return "", 0, fmt.Errorf("%s: %s: %s", resp.Status, result.ErrCode, result.Error)
+		}
+		return "", 0, fmt.Errorf("%s: %s", resp.Status, strings.TrimSpace(string(body)))
+	case result.EventID == "":
+		return "", 0, errors.New("response has no event_id")
+	}
+	return result.EventID, 0, nil
+}
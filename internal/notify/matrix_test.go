@@ -0,0 +1,93 @@
+package notify
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestMatrixNotifierEditsPreviousMessage(t *testing.T) {
+	var (
+		events []matrixContent
+		paths  []string
+	)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPut || r.Header.Get("Authorization") != "Bearer syt_test" {
+			t.Errorf("unexpected request %s with auth %q", r.Method, r.Header.Get("Authorization"))
+		}
+		var content matrixContent
+		if err := json.NewDecoder(r.Body).Decode(&content); err != nil {
+			t.Errorf("decode event: %v", err)
+		}
+		events = append(events, content)
+		paths = append(paths, r.URL.EscapedPath())
+		fmt.Fprintf(w, `{"event_id":"$event%d"}`, len(events))
+	}))
+	defer server.Close()
+
+	n, err := NewMatrixNotifier(MatrixConfig{Homeserver: server.URL, RoomID: "!room:example.org", Token: "syt_test"})
+	if err != nil {
+		t.Fatalf("NewMatrixNotifier error = %v", err)
+	}
+	ctx := context.Background()
+	link := "https://github.com/deseretdigital/example/pull/42"
+	if err := n.Notify(ctx, Event{Repo: "deseretdigital/example", Number: 42, Title: "Add <widget>", Summary: "Review requested", URL: link}); err != nil {
+		t.Fatalf("Notify error = %v", err)
+	}
+	if err := n.Notify(ctx, Event{Repo: "deseretdigital/example", Number: 42, Title: "Add <widget>", Summary: "New comment by lead", URL: link + "#issuecomment-1"}); err != nil {
+		t.Fatalf("Notify error = %v", err)
+	}
+	if err := n.Notify(ctx, Event{Repo: "deseretdigital/example", Number: 43, Title: "Other PR", Summary: "Review requested", URL: "https://github.com/deseretdigital/example/pull/43"}); err != nil {
+		t.Fatalf("Notify error = %v", err)
+	}
+
+	if len(events) != 3 {
+		t.Fatalf("expected 3 events, got %d", len(events))
+	}
+	if !strings.HasPrefix(paths[0], "/_matrix/client/v3/rooms/%21room:example.org/send/m.room.message/") || paths[0] == paths[1] {
+		t.Errorf("unexpected paths: %v", paths)
+	}
+	first := events[0]
+	if first.RelatesTo != nil || !strings.Contains(first.FormattedBody, "Add &lt;widget&gt;") || first.Format != "org.matrix.custom.html" {
+		t.Errorf("unexpected first event: %+v", first)
+	}
+	edit := events[1]
+	if edit.RelatesTo == nil || edit.RelatesTo.RelType != "m.replace" || edit.RelatesTo.EventID != "$event1" {
+		t.Fatalf("expected an edit of $event1, got %+v", edit.RelatesTo)
+	}
+	if edit.NewContent == nil || !strings.Contains(edit.NewContent.Body, "New comment by lead") || !strings.HasPrefix(edit.Body, "* ") {
+		t.Errorf("unexpected edit content: %+v", edit)
+	}
+	if events[2].RelatesTo != nil {
+		t.Errorf("a different PR should get a new message, got %+v", events[2].RelatesTo)
+	}
+}
+
+func TestMatrixNotifierRetriesRateLimit(t *testing.T) {
+	var calls int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		if calls == 1 {
+			w.WriteHeader(http.StatusTooManyRequests)
+			w.Write([]byte(`{"errcode":"M_LIMIT_EXCEEDED","error":"Too many requests","retry_after_ms":5}`))
+			return
+		}
+		w.Write([]byte(`{"event_id":"$ok"}`))
+	}))
+	defer server.Close()
+
+	n, err := NewMatrixNotifier(MatrixConfig{Homeserver: server.URL, RoomID: "!room:example.org", Token: "syt_test"})
+	if err != nil {
+		t.Fatalf("NewMatrixNotifier error = %v", err)
+	}
+	if err := n.Notify(context.Background(), Event{Title: "Title", Summary: "Message"}); err != nil {
+		t.Fatalf("Notify error = %v", err)
+	}
+	if calls != 2 {
+		t.Errorf("expected 2 calls, got %d", calls)
+	}
+}
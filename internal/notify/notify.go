@@ -5,23 +5,34 @@ import (
 	"fmt"
 	"log/slog"
 	"os/exec"
+	"runtime"
 	"strings"
 	"unicode/utf8"
 )
 
 type Notifier interface {
-	Notify(ctx context.Context, title, subtitle, message, link string) error
+	Notify(ctx context.Context, event Event) error
 }
 
-type notifier struct {
-	logger *slog.Logger
+// NewNotifier returns the desktop notifier for the current platform:
+// Notification Center dialogs on macOS, and the freedesktop notification
+// service over D-Bus on Linux and the BSDs. Other platforms have no desktop
+// notifier.
+func NewNotifier(logger *slog.Logger) (Notifier, error) {
+	switch runtime.GOOS {
+	case "darwin":
+		return &osascriptNotifier{}, nil
+	case "linux", "freebsd", "openbsd", "netbsd", "dragonfly":
+		return NewDBusNotifier(logger), nil
+	}
+	return nil, fmt.Errorf("desktop notifications are not supported on %s; pick another -notifier backend", runtime.GOOS)
 }
 
-func NewNotifier(logger *slog.Logger) Notifier {
-	return &notifier{logger: logger}
-}
+type osascriptNotifier struct{}
 
-func (n *notifier) Notify(ctx context.Context, title, subtitle, message, link string) error {
+func (n *osascriptNotifier) Notify(ctx context.Context, event Event) error {
+	title, subtitle, message := event.Text()
+	link := event.URL
 	title = truncateForNotification(title, 128)
 	subtitle = truncateForNotification(subtitle, 256)
 	message = truncateForNotification(message, 512)
@@ -55,9 +66,6 @@ end if`, openButton, link)
 	}
 	cmd := exec.CommandContext(ctx, "osascript", "-e", script)
 	if err := cmd.Run(); err != nil {
-		if n.logger != nil {
-			n.logger.Warn("failed to send notification", slog.String("error", err.Error()))
-		}
 		return fmt.Errorf("osascript notification: %w", err)
 	}
 	return nil
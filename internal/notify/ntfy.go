@@ -0,0 +1,142 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+const (
+	ntfyServer  = "https://ntfy.sh"
+	ntfyTimeout = 10 * time.Second
+
+	ntfyPriorityDefault = 3
+	ntfyPriorityHigh    = 4
+)
+
+// NtfyConfig configures the ntfy push notifier.
+type NtfyConfig struct {
+	// Server defaults to https://ntfy.sh.
+	Server string
+	Topic  string
+	// Token is an access token for protected topics on self-hosted servers.
+	Token      string
+	HTTPClient *http.Client
+}
+
+type ntfyNotifier struct {
+	cfg    NtfyConfig
+	client *http.Client
+}
+
+// NewNtfyNotifier returns a notifier that publishes to an ntfy topic.
+func NewNtfyNotifier(cfg NtfyConfig) (Notifier, error) {
+	if cfg.Topic == "" {
+		return nil, errors.New("ntfy: a topic is required")
+	}
+	if cfg.Server == "" {
+		cfg.Server = ntfyServer
+	}
+	client := cfg.HTTPClient
+	if client == nil {
+		client = &http.Client{Timeout: ntfyTimeout}
+	}
+	return &ntfyNotifier{cfg: cfg, client: client}, nil
+}
+
+type ntfyMessage struct {
+	Topic    string       `json:"topic"`
+	Title    string       `json:"title,omitempty"`
+	Message  string       `json:"message"`
+	Priority int          `json:"priority"`
+	Tags     []string     `json:"tags,omitempty"`
+	Click    string       `json:"click,omitempty"`
+	Actions  []ntfyAction `json:"actions,omitempty"`
+}
+
+type ntfyAction struct {
+	Action string `json:"action"`
+	Label  string `json:"label"`
+	URL    string `json:"url"`
+}
+
+func (n *ntfyNotifier) Notify(ctx context.Context, event Event) error {
+	title, subtitle, message := event.Text()
+	msg := ntfyMessage{
+		Topic:    n.cfg.Topic,
+		Title:    truncateForNotification(title, 128),
+		Message:  strings.TrimSpace(truncateForNotification(subtitle, 256) + "\n" + truncateForNotification(message, 512)),
+		Priority: ntfyPriorityDefault,
+		Tags:     sizeTags(event.Diff),
+	}
+	if event.critical() {
+		msg.Priority = ntfyPriorityHigh
+		msg.Tags = append(msg.Tags, "rotating_light")
+	}
+	if link := strings.TrimSpace(event.URL); link != "" {
+		msg.Click = link
+		msg.Actions = []ntfyAction{{Action: "view", Label: "Open", URL: link}}
+	}
+
+	if err := n.publish(ctx, msg); err != nil {
+		return fmt.Errorf("ntfy notification: %w", err)
+	}
+	return nil
+}
+
+func (n *ntfyNotifier) publish(ctx context.Context, msg ntfyMessage) error {
+	payload, err := json.Marshal(msg)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, strings.TrimSuffix(n.cfg.Server, "/"), bytes.NewReader(payload))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if n.cfg.Token != "" {
+		req.Header.Set("Authorization", "Bearer "+n.cfg.Token)
+	}
+	resp, err := n.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 4<<10))
+		var result struct {
+			Error string `json:"error"`
+		}
+		if json.Unmarshal(body, &result) == nil && result.Error != "" {
+			return fmt.Errorf("%s: %s", resp.Status, result.Error)
+		}
+		return fmt.Errorf("%s: %s", resp.Status, strings.TrimSpace(string(body)))
+	}
+	return nil
+}
+
+// sizeTags turns a PR's diff stats into an emoji tag and a size label, from
+// a mouse for a handful of lines to an elephant.
+func sizeTags(diff *DiffStats) []string {
+	if diff == nil {
+		return nil
+	}
+	switch lines := diff.Lines(); {
+	case lines <= 10:
+		return []string{"mouse", "size:XS"}
+	case lines <= 100:
+		return []string{"rabbit", "size:S"}
+	case lines <= 500:
+		return []string{"dog", "size:M"}
+	case lines <= 1000:
+		return []string{"horse", "size:L"}
+	default:
+		return []string{"elephant", "size:XL"}
+	}
+}
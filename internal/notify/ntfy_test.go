@@ -0,0 +1,86 @@
+package notify
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestNtfyNotifierPublishes(t *testing.T) {
+	var (
+		got  ntfyMessage
+		auth string
+	)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		auth = r.Header.Get("Authorization")
+		if err := json.NewDecoder(r.Body).Decode(&got); err != nil {
+			t.Errorf("decode message: %v", err)
+		}
+		w.Write([]byte(`{"id":"abc"}`))
+	}))
+	defer server.Close()
+
+	n, err := NewNtfyNotifier(NtfyConfig{Server: server.URL, Topic: "reviews", Token: "tk_test"})
+	if err != nil {
+		t.Fatalf("NewNtfyNotifier error = %v", err)
+	}
+	link := "https://github.com/deseretdigital/example/pull/42"
+	event := Event{
+		Kind:    KindReviewRequested,
+		Repo:    "deseretdigital/example",
+		Number:  42,
+		Title:   "Add widget",
+		Summary: "Review requested",
+		Diff:    &DiffStats{Additions: 40, Deletions: 12, ChangedFiles: 3},
+		URL:     link,
+	}
+	if err := n.Notify(context.Background(), event); err != nil {
+		t.Fatalf("Notify error = %v", err)
+	}
+
+	if auth != "Bearer tk_test" {
+		t.Errorf("Authorization = %q", auth)
+	}
+	if got.Topic != "reviews" || got.Title != "Add widget" || got.Priority != ntfyPriorityDefault {
+		t.Errorf("unexpected message: %+v", got)
+	}
+	if !strings.HasPrefix(got.Message, "deseretdigital/example\n") {
+		t.Errorf("message = %q", got.Message)
+	}
+	if want := []string{"rabbit", "size:S"}; !reflect.DeepEqual(got.Tags, want) {
+		t.Errorf("tags = %v, want %v", got.Tags, want)
+	}
+	if got.Click != link || len(got.Actions) != 1 || got.Actions[0].Label != "Open" || got.Actions[0].URL != link {
+		t.Errorf("unexpected click/actions: %q %+v", got.Click, got.Actions)
+	}
+}
+
+func TestNtfyNotifierReportsErrors(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusForbidden)
+		w.Write([]byte(`{"code":40301,"http":403,"error":"forbidden"}`))
+	}))
+	defer server.Close()
+
+	n, err := NewNtfyNotifier(NtfyConfig{Server: server.URL, Topic: "reviews"})
+	if err != nil {
+		t.Fatalf("NewNtfyNotifier error = %v", err)
+	}
+	err = n.Notify(context.Background(), Event{Kind: KindWorkflowFailed, Repo: "deseretdigital/example", Title: "CI failing on main"})
+	if err == nil || !strings.Contains(err.Error(), "forbidden") {
+		t.Fatalf("expected forbidden error, got %v", err)
+	}
+}
+
+func TestSizeTags(t *testing.T) {
+	if tags := sizeTags(nil); tags != nil {
+		t.Errorf("expected no tags without diff stats, got %v", tags)
+	}
+	if tags := sizeTags(&DiffStats{Additions: 900, Deletions: 300, ChangedFiles: 40}); tags[1] != "size:XL" {
+		t.Errorf("expected XL, got %v", tags)
+	}
+}
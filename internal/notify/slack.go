@@ -0,0 +1,265 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	slackAPIURL      = "https://slack.com/api"
+	slackTimeout     = 10 * time.Second
+	slackMaxAttempts = 3
+	// slackMinInterval keeps posts under Slack's limit of roughly one
+	// message per second per channel.
+	slackMinInterval = time.Second
+)
+
+// SlackConfig selects how notifications reach Slack: an incoming webhook,
+// or chat.postMessage with a bot token and channel.
+type SlackConfig struct {
+	WebhookURL string
+	Token      string
+	Channel    string
+	// APIURL overrides the Slack Web API base URL.
+	APIURL     string
+	HTTPClient *http.Client
+}
+
+type slackNotifier struct {
+	cfg      SlackConfig
+	client   *http.Client
+	interval time.Duration
+
+	mu   sync.Mutex
+	next time.Time
+}
+
+// NewSlackNotifier returns a notifier that posts Block Kit messages to
+// Slack.
+func NewSlackNotifier(cfg SlackConfig) (Notifier, error) {
+	switch {
+	case cfg.Token != "" && cfg.Channel == "":
+		return nil, errors.New("slack: a channel is required with a bot token")
+	case cfg.Token == "" && cfg.WebhookURL == "":
+		return nil, errors.New("slack: a webhook URL or bot token is required")
+	}
+	if cfg.APIURL == "" {
+		cfg.APIURL = slackAPIURL
+	}
+	client := cfg.HTTPClient
+	if client == nil {
+		client = &http.Client{Timeout: slackTimeout}
+	}
+	return &slackNotifier{
+		cfg:      cfg,
+		client:   client,
+		interval: slackMinInterval,
+	}, nil
+}
+
+type slackMessage struct {
+	Channel     string       `json:"channel,omitempty"`
+	Text        string       `json:"text"`
+	Blocks      []slackBlock `json:"blocks"`
+	UnfurlLinks bool         `json:"unfurl_links"`
+}
+
+type slackBlock struct {
+	Type     string      `json:"type"`
+	Text     *slackText  `json:"text,omitempty"`
+	Fields   []slackText `json:"fields,omitempty"`
+	Elements []any       `json:"elements,omitempty"`
+}
+
+type slackText struct {
+	Type string `json:"type"`
+	Text string `json:"text"`
+}
+
+type slackButton struct {
+	Type string    `json:"type"`
+	Text slackText `json:"text"`
+	URL  string    `json:"url"`
+}
+
+func (s *slackNotifier) Notify(ctx context.Context, event Event) error {
+	payload, err := json.Marshal(s.message(event))
+	if err != nil {
+		return fmt.Errorf("encode slack message: %w", err)
+	}
+
+	for attempt := 1; ; attempt++ {
+		if err := s.pace(ctx); err != nil {
+			return err
+		}
+		retryAfter, err := s.post(ctx, payload)
+		if err == nil {
+			return nil
+		}
+		if retryAfter <= 0 || attempt == slackMaxAttempts {
+			return fmt.Errorf("slack notification: %w", err)
+		}
+		s.mu.Lock()
+		if until := time.Now().Add(retryAfter); until.After(s.next) {
+			s.next = until
+		}
+		s.mu.Unlock()
+	}
+}
+
+func (s *slackNotifier) message(event Event) slackMessage {
+	title, subtitle, message := event.Text()
+	title = strings.TrimSpace(title)
+	link := strings.TrimSpace(event.URL)
+	heading := "*" + markupEscaper.Replace(title) + "*"
+	if link != "" {
+		heading = fmt.Sprintf("*<%s|%s>*", link, markupEscaper.Replace(title))
+	}
+
+	blocks := []slackBlock{{
+		Type: "section",
+		Text: &slackText{Type: "mrkdwn", Text: heading},
+	}}
+	if subtitle = strings.TrimSpace(subtitle); subtitle != "" {
+		blocks = append(blocks, slackBlock{
+			Type:     "context",
+			Elements: []any{slackText{Type: "mrkdwn", Text: markupEscaper.Replace(subtitle)}},
+		})
+	}
+	message = strings.TrimSpace(message)
+	if fields := slackFields(event); message != "" || len(fields) > 0 {
+		section := slackBlock{Type: "section", Fields: fields}
+		if message != "" {
+			section.Text = &slackText{Type: "mrkdwn", Text: markupEscaper.Replace(truncateForNotification(message, 2900))}
+		}
+		blocks = append(blocks, section)
+	}
+	if link != "" {
+		blocks = append(blocks, slackBlock{
+			Type: "actions",
+			Elements: []any{slackButton{
+				Type: "button",
+				Text: slackText{Type: "plain_text", Text: "Open PR"},
+				URL:  link,
+			}},
+		})
+	}
+
+	text := title
+	if message != "" {
+		text = fmt.Sprintf("%s: %s", title, message)
+	}
+	msg := slackMessage{Text: markupEscaper.Replace(text), Blocks: blocks}
+	if s.cfg.Token != "" {
+		msg.Channel = s.cfg.Channel
+	}
+	return msg
+}
+
+// slackFields lays out who caused the event and the size and review state of
+// a PR side by side.
+func slackFields(event Event) []slackText {
+	var fields []slackText
+	if event.Actor != "" {
+		label := "By"
+		switch event.Kind {
+		case KindReview, KindReviewState:
+			label = "Reviewer"
+		case KindReviewRequested, KindPullRequestUpdated, KindPullRequestOpened:
+			label = "Author"
+		}
+		fields = append(fields, slackText{Type: "mrkdwn", Text: fmt.Sprintf("*%s*\n%s", label, markupEscaper.Replace(event.Actor))})
+	}
+	if event.Diff != nil {
+		fields = append(fields, slackText{
+			Type: "mrkdwn",
+			Text: fmt.Sprintf("*Size*\n+%d −%d · %d files", event.Diff.Additions, event.Diff.Deletions, event.Diff.ChangedFiles),
+		})
+	}
+	if event.ReviewState != "" {
+		fields = append(fields, slackText{Type: "mrkdwn", Text: "*Review*\n" + markupEscaper.Replace(event.ReviewState)})
+	}
+	return fields
+}
+
+// pace waits for the next free posting slot.
+func (s *slackNotifier) pace(ctx context.Context) error {
+	s.mu.Lock()
+	now := time.Now()
+	start := s.next
+	if start.Before(now) {
+		start = now
+	}
+	s.next = start.Add(s.interval)
+	s.mu.Unlock()
+
+	return sleep(ctx, start.Sub(now))
+}
+
+// post sends one message. A positive duration means Slack rate limited the
+// request and it may be retried after that long.
+func (s *slackNotifier) post(ctx context.Context, payload []byte) (time.Duration, error) {
+	endpoint := s.cfg.WebhookURL
+	if s.cfg.Token != "" {
+		endpoint = strings.TrimSuffix(s.cfg.APIURL, "/") + "/chat.postMessage"
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewReader(payload))
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("Content-Type", "application/json; charset=utf-8")
+	if s.cfg.Token != "" {
+		req.Header.Set("Authorization", "Bearer "+s.cfg.Token)
+	}
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, 64<<10))
+
+	if resp.StatusCode == http.StatusTooManyRequests {
+		return retryAfter(resp.Header.Get("Retry-After")), errors.New("rate limited")
+	}
+	if resp.StatusCode >= 300 {
+		return 0, fmt.Errorf("%s: %s", resp.Status, strings.TrimSpace(string(body)))
+	}
+	if s.cfg.Token == "" {
+		return 0, nil
+	}
+
+	var result struct {
+		OK    bool   `json:"ok"`
+		Error string `json:"error"`
+	}
+	if err := json.Unmarshal(body, &result); err != nil {
+		return 0, fmt.Errorf("decode chat.postMessage response: %w", err)
+	}
+	if !result.OK {
+		if result.Error == "ratelimited" {
+			return retryAfter(resp.Header.Get("Retry-After")), errors.New("rate limited")
+		}
+		return 0, fmt.Errorf("chat.postMessage: %s", result.Error)
+	}
+	return 0, nil
+}
+
+// retryAfter parses a Retry-After header in seconds, defaulting to one
+// second.
+func retryAfter(header string) time.Duration {
+	seconds, err := strconv.Atoi(strings.TrimSpace(header))
+	if err != nil || seconds <= 0 {
+		return time.Second
+	}
+	return time.Duration(seconds) * time.Second
+}
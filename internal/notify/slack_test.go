@@ -0,0 +1,138 @@
+package notify
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestSlackNotifierWebhook(t *testing.T) {
+	var got slackMessage
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := json.NewDecoder(r.Body).Decode(&got); err != nil {
+			t.Errorf("decode payload: %v", err)
+		}
+		io.WriteString(w, "ok")
+	}))
+	defer server.Close()
+
+	n, err := NewSlackNotifier(SlackConfig{WebhookURL: server.URL})
+	if err != nil {
+		t.Fatalf("NewSlackNotifier error = %v", err)
+	}
+	link := "https://github.com/deseretdigital/example/pull/42"
+	event := Event{
+		Kind:    KindReviewRequested,
+		Repo:    "deseretdigital/example",
+		Number:  42,
+		Title:   "Fix <thing> & more",
+		Summary: "Review requested",
+		Actor:   "dev",
+		Diff:    &DiffStats{Additions: 10, Deletions: 2, ChangedFiles: 3},
+		URL:     link,
+	}
+	if err := n.Notify(context.Background(), event); err != nil {
+		t.Fatalf("Notify error = %v", err)
+	}
+
+	if got.Channel != "" {
+		t.Errorf("webhook payload should not set a channel, got %q", got.Channel)
+	}
+	if len(got.Blocks) != 4 {
+		t.Fatalf("expected 4 blocks, got %d", len(got.Blocks))
+	}
+	if want := "*<" + link + "|Fix &lt;thing&gt; &amp; more>*"; got.Blocks[0].Text.Text != want {
+		t.Errorf("heading = %q, want %q", got.Blocks[0].Text.Text, want)
+	}
+	if got.Blocks[1].Type != "context" || got.Blocks[2].Text.Text != "Review requested · #42 · +10 −2 · 3 files" {
+		t.Errorf("unexpected context/message blocks: %+v", got.Blocks[1:3])
+	}
+	if fields := got.Blocks[2].Fields; len(fields) != 2 || fields[0].Text != "*Author*\ndev" || fields[1].Text != "*Size*\n+10 −2 · 3 files" {
+		t.Errorf("unexpected fields: %+v", fields)
+	}
+	button := got.Blocks[3].Elements[0].(map[string]any)
+	if got.Blocks[3].Type != "actions" || button["url"] != link {
+		t.Errorf("unexpected actions block: %+v", got.Blocks[3])
+	}
+}
+
+func TestSlackMessageFieldsWithoutMessage(t *testing.T) {
+	s := &slackNotifier{}
+	msg := s.message(Event{Kind: KindReviewState, Title: "Fix thing", Actor: "lead", ReviewState: "Approved"})
+	if len(msg.Blocks) != 2 {
+		t.Fatalf("expected heading and fields blocks, got %+v", msg.Blocks)
+	}
+	section := msg.Blocks[1]
+	if section.Text != nil {
+		t.Errorf("unexpected section text %+v", section.Text)
+	}
+	if len(section.Fields) != 2 || section.Fields[0].Text != "*Reviewer*\nlead" || section.Fields[1].Text != "*Review*\nApproved" {
+		t.Errorf("unexpected fields: %+v", section.Fields)
+	}
+}
+
+func TestSlackNotifierBotTokenRetriesRateLimit(t *testing.T) {
+	var calls int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		if r.URL.Path != "/chat.postMessage" {
+			t.Errorf("unexpected path %q", r.URL.Path)
+		}
+		if auth := r.Header.Get("Authorization"); auth != "Bearer xoxb-test" {
+			t.Errorf("Authorization = %q", auth)
+		}
+		if calls == 1 {
+			w.Header().Set("Retry-After", "0")
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		var msg slackMessage
+		json.NewDecoder(r.Body).Decode(&msg)
+		if msg.Channel != "#reviews" {
+			t.Errorf("channel = %q", msg.Channel)
+		}
+		io.WriteString(w, `{"ok":true}`)
+	}))
+	defer server.Close()
+
+	n, err := NewSlackNotifier(SlackConfig{Token: "xoxb-test", Channel: "#reviews", APIURL: server.URL})
+	if err != nil {
+		t.Fatalf("NewSlackNotifier error = %v", err)
+	}
+	n.(*slackNotifier).interval = 0
+	if err := n.Notify(context.Background(), Event{Title: "Title", Summary: "Message"}); err != nil {
+		t.Fatalf("Notify error = %v", err)
+	}
+	if calls != 2 {
+		t.Errorf("expected a retry after rate limiting, got %d calls", calls)
+	}
+}
+
+func TestSlackNotifierAPIError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		io.WriteString(w, `{"ok":false,"error":"channel_not_found"}`)
+	}))
+	defer server.Close()
+
+	n, err := NewSlackNotifier(SlackConfig{Token: "xoxb-test", Channel: "#missing", APIURL: server.URL})
+	if err != nil {
+		t.Fatalf("NewSlackNotifier error = %v", err)
+	}
+	err = n.Notify(context.Background(), Event{Title: "Title", Summary: "Message"})
+	if err == nil || !strings.Contains(err.Error(), "channel_not_found") {
+		t.Fatalf("expected channel_not_found error, got %v", err)
+	}
+}
+
+func TestNewSlackNotifierValidates(t *testing.T) {
+	if _, err := NewSlackNotifier(SlackConfig{}); err == nil {
+		t.Errorf("expected error without webhook or token")
+	}
+	if _, err := NewSlackNotifier(SlackConfig{Token: "xoxb-test"}); err == nil {
+		t.Errorf("expected error for token without channel")
+	}
+}
@@ -0,0 +1,236 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// WebhookEventVersion is the version of the JSON document posted by the
+// webhook notifier. It changes only when fields are removed or change
+// meaning; new fields may be added within a version.
+const WebhookEventVersion = 1
+
+const (
+	webhookTimeout     = 10 * time.Second
+	webhookMaxAttempts = 3
+	webhookBackoff     = time.Second
+	webhookExcerptLen  = 1000
+)
+
+// WebhookConfig configures the outbound webhook notifier.
+type WebhookConfig struct {
+	URL string
+	// Secret signs each request body with HMAC-SHA256, sent as
+	// "X-Signature: sha256=<hex>". Requests are unsigned without it.
+	Secret  string
+	Headers map[string]string
+	// Timeout bounds each attempt; MaxAttempts and Backoff control retries
+	// of failed deliveries, with the backoff doubling after each attempt.
+	Timeout     time.Duration
+	MaxAttempts int
+	Backoff     time.Duration
+	HTTPClient  *http.Client
+}
+
+// WebhookEvent is the JSON document posted for each notification, also
+// passed to exec hooks on stdin. Kind, Repo, Number, Actor and OccurredAt are
+// taken from the Event as reported, never guessed from its link.
+type WebhookEvent struct {
+	Version int `json:"version"`
+	// ID is derived from the event's dedupe key, or its content when it has
+	// none, so the same notification sent twice carries the same ID.
+	ID          string     `json:"id"`
+	Kind        Kind       `json:"kind"`
+	Repo        string     `json:"repo,omitempty"`
+	Number      int        `json:"number,omitempty"`
+	Title       string     `json:"title"`
+	Subtitle    string     `json:"subtitle,omitempty"`
+	Body        string     `json:"body,omitempty"`
+	Actor       string     `json:"actor,omitempty"`
+	ReviewState string     `json:"review_state,omitempty"`
+	Diff        *DiffStats `json:"diff,omitempty"`
+	URL         string     `json:"url,omitempty"`
+	OccurredAt  *time.Time `json:"occurred_at,omitempty"`
+	SentAt      time.Time  `json:"sent_at"`
+}
+
+func newWebhookEvent(e Event) WebhookEvent {
+	title, subtitle, message := e.Text()
+	event := WebhookEvent{
+		Version:     WebhookEventVersion,
+		Kind:        e.Kind,
+		Repo:        e.Repo,
+		Number:      e.Number,
+		Title:       strings.TrimSpace(title),
+		Subtitle:    strings.TrimSpace(subtitle),
+		Body:        truncateForNotification(message, webhookExcerptLen),
+		Actor:       e.Actor,
+		ReviewState: e.ReviewState,
+		Diff:        e.Diff,
+		URL:         strings.TrimSpace(e.URL),
+		SentAt:      time.Now().UTC(),
+	}
+	if !e.OccurredAt.IsZero() {
+		occurred := e.OccurredAt.UTC()
+		event.OccurredAt = &occurred
+	}
+	if e.DedupeKey != "" {
+		event.ID = eventID(e.DedupeKey)
+	} else {
+		event.ID = eventID(event.Title, event.Subtitle, message, event.URL)
+	}
+	return event
+}
+
+// eventID hashes the fields that identify a notification.
+func eventID(fields ...string) string {
+	h := sha256.New()
+	for _, field := range fields {
+		h.Write([]byte(strings.TrimSpace(field)))
+		h.Write([]byte{0})
+	}
+	return hex.EncodeToString(h.Sum(nil)[:16])
+}
+
+type webhookNotifier struct {
+	cfg    WebhookConfig
+	client *http.Client
+}
+
+// NewWebhookNotifier returns a notifier that POSTs a WebhookEvent to a URL.
+func NewWebhookNotifier(cfg WebhookConfig) (Notifier, error) {
+	if cfg.URL == "" {
+		return nil, errors.New("webhook: a URL is required")
+	}
+	if cfg.Timeout <= 0 {
+		cfg.Timeout = webhookTimeout
+	}
+	if cfg.MaxAttempts <= 0 {
+		cfg.MaxAttempts = webhookMaxAttempts
+	}
+	if cfg.Backoff <= 0 {
+		cfg.Backoff = webhookBackoff
+	}
+	client := cfg.HTTPClient
+	if client == nil {
+		client = &http.Client{}
+	}
+	return &webhookNotifier{cfg: cfg, client: client}, nil
+}
+
+func (w *webhookNotifier) Notify(ctx context.Context, event Event) error {
+	payload, err := json.Marshal(newWebhookEvent(event))
+	if err != nil {
+		return fmt.Errorf("encode webhook event: %w", err)
+	}
+
+	backoff := w.cfg.Backoff
+	for attempt := 1; ; attempt++ {
+		wait, err := w.post(ctx, payload)
+		if err == nil {
+			return nil
+		}
+		if wait < 0 || attempt == w.cfg.MaxAttempts {
+			return fmt.Errorf("webhook notification: %w", err)
+		}
+		if wait == 0 {
+			wait = backoff
+			backoff *= 2
+		}
+		if err := sleep(ctx, wait); err != nil {
+			return err
+		}
+	}
+}
+
+// post delivers one attempt. On failure it returns how long to wait before
+// retrying: zero for the default backoff, or negative if the request should
+// not be retried.
+func (w *webhookNotifier) post(ctx context.Context, payload []byte) (time.Duration, error) {
+	ctx, cancel := context.WithTimeout(ctx, w.cfg.Timeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, w.cfg.URL, bytes.NewReader(payload))
+	if err != nil {
+		return -1, err
+	}
+	for name, value := range w.cfg.Headers {
+		req.Header.Set(name, value)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "gh-review-notifier")
+	req.Header.Set("X-Event-Version", strconv.Itoa(WebhookEventVersion))
+	if w.cfg.Secret != "" {
+		req.Header.Set("X-Signature", signPayload(w.cfg.Secret, payload))
+	}
+
+	resp, err := w.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, 4<<10))
+
+	switch {
+	case resp.StatusCode < 300:
+		return 0, nil
+	case resp.StatusCode == http.StatusTooManyRequests:
+		return retryAfter(resp.Header.Get("Retry-After")), errors.New(resp.Status)
+	case resp.StatusCode >= 500:
+		return 0, fmt.Errorf("%s: %s", resp.Status, strings.TrimSpace(string(body)))
+	default:
+		return -1, fmt.Errorf("%s: %s", resp.Status, strings.TrimSpace(string(body)))
+	}
+}
+
+// signPayload returns the X-Signature value for a request body.
+func signPayload(secret string, payload []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(payload)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// linkSubject extracts the repository and pull request or issue number
+// from a GitHub link, if it points at one.
+func linkSubject(link string) (string, int) {
+	u, err := url.Parse(link)
+	if err != nil {
+		return "", 0
+	}
+	parts := strings.Split(strings.Trim(u.Path, "/"), "/")
+	if len(parts) < 2 {
+		return "", 0
+	}
+	repo := parts[0] + "/" + parts[1]
+	if len(parts) < 4 || (parts[2] != "pull" && parts[2] != "issues") {
+		return repo, 0
+	}
+	number, err := strconv.Atoi(parts[3])
+	if err != nil {
+		return repo, 0
+	}
+	return repo, number
+}
+
+func sleep(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,145 @@
+package notify
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestWebhookNotifierSignsEvent(t *testing.T) {
+	var (
+		event     WebhookEvent
+		signature string
+		token     string
+	)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		if err := json.Unmarshal(body, &event); err != nil {
+			t.Errorf("decode event: %v", err)
+		}
+		if want := signPayload("s3cret", body); r.Header.Get("X-Signature") != want {
+			t.Errorf("X-Signature = %q, want %q", r.Header.Get("X-Signature"), want)
+		}
+		signature = r.Header.Get("X-Signature")
+		token = r.Header.Get("Authorization")
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	n, err := NewWebhookNotifier(WebhookConfig{
+		URL:     server.URL,
+		Secret:  "s3cret",
+		Headers: map[string]string{"Authorization": "Bearer abc"},
+	})
+	if err != nil {
+		t.Fatalf("NewWebhookNotifier error = %v", err)
+	}
+	link := "https://github.com/deseretdigital/example/pull/42#issuecomment-1"
+	occurred := time.Date(2026, 3, 2, 15, 4, 5, 0, time.UTC)
+	if err := n.Notify(context.Background(), Event{
+		Kind:       KindComment,
+		Repo:       "deseretdigital/example",
+		Number:     42,
+		Title:      "Add widget",
+		Actor:      "lead",
+		Body:       "looks good",
+		URL:        link,
+		OccurredAt: occurred,
+		DedupeKey:  "comment:1",
+	}); err != nil {
+		t.Fatalf("Notify error = %v", err)
+	}
+
+	if signature == "" || token != "Bearer abc" {
+		t.Errorf("signature = %q, Authorization = %q", signature, token)
+	}
+	if event.Version != WebhookEventVersion || event.Repo != "deseretdigital/example" || event.Number != 42 {
+		t.Errorf("unexpected event subject: %+v", event)
+	}
+	if event.Title != "Add widget" || event.Body != "lead: looks good" || event.URL != link || event.SentAt.IsZero() {
+		t.Errorf("unexpected event: %+v", event)
+	}
+	if event.Kind != KindComment || event.Actor != "lead" || event.OccurredAt == nil || !event.OccurredAt.Equal(occurred) {
+		t.Errorf("unexpected event facts: %+v", event)
+	}
+	if event.ID != eventID("comment:1") {
+		t.Errorf("ID = %q, want one derived from the dedupe key", event.ID)
+	}
+}
+
+func TestNewWebhookEventKeepsReportedSubject(t *testing.T) {
+	event := newWebhookEvent(Event{Kind: KindDeployment, Title: "Deploy waiting", URL: "https://github.com/deseretdigital/example/pull/42"})
+	if event.Kind != KindDeployment {
+		t.Errorf("kind = %q", event.Kind)
+	}
+	if event.Repo != "" || event.Number != 0 {
+		t.Errorf("subject guessed from link: %q #%d", event.Repo, event.Number)
+	}
+}
+
+func TestWebhookNotifierRetries(t *testing.T) {
+	var calls int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		if calls < 3 {
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	n, err := NewWebhookNotifier(WebhookConfig{URL: server.URL, Backoff: time.Millisecond})
+	if err != nil {
+		t.Fatalf("NewWebhookNotifier error = %v", err)
+	}
+	if err := n.Notify(context.Background(), Event{Title: "Title", Summary: "Message"}); err != nil {
+		t.Fatalf("Notify error = %v", err)
+	}
+	if calls != 3 {
+		t.Errorf("expected 3 attempts, got %d", calls)
+	}
+}
+
+func TestWebhookNotifierDoesNotRetryClientErrors(t *testing.T) {
+	var calls int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.WriteHeader(http.StatusUnauthorized)
+	}))
+	defer server.Close()
+
+	n, err := NewWebhookNotifier(WebhookConfig{URL: server.URL, Backoff: time.Millisecond})
+	if err != nil {
+		t.Fatalf("NewWebhookNotifier error = %v", err)
+	}
+	if err := n.Notify(context.Background(), Event{Title: "Title", Summary: "Message"}); err == nil {
+		t.Fatal("expected an error for 401")
+	}
+	if calls != 1 {
+		t.Errorf("expected 1 attempt, got %d", calls)
+	}
+}
+
+func TestLinkSubject(t *testing.T) {
+	tests := []struct {
+		link   string
+		repo   string
+		number int
+	}{
+		{"https://github.com/deseretdigital/example/pull/42", "deseretdigital/example", 42},
+		{"https://github.com/deseretdigital/example/issues/7#issuecomment-1", "deseretdigital/example", 7},
+		{"https://github.com/deseretdigital/example/actions/runs/99", "deseretdigital/example", 0},
+		{"", "", 0},
+	}
+	for _, tt := range tests {
+		repo, number := linkSubject(tt.link)
+		if repo != tt.repo || number != tt.number {
+			t.Errorf("linkSubject(%q) = %q, %d; want %q, %d", tt.link, repo, number, tt.repo, tt.number)
+		}
+	}
+}